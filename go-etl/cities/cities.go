@@ -0,0 +1,10 @@
+// Package cities embeds each city's station naming data (cities/<city>/*.csv) as a
+// fallback for binaries run away from the repository. See internal/naming.
+package cities
+
+import "embed"
+
+// FS holds the naming files of every city, at <city>/<file>.csv
+//
+//go:embed */*.csv
+var FS embed.FS
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/naming"
+)
+
+var aliasesCmd = &cobra.Command{
+	Use:   "aliases",
+	Short: "Manage station aliases used to match ridership names to stations",
+}
+
+var aliasesImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Load aliases from a CSV file (default: the city's cities/<city>/aliases.csv)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		cityAdapter := mustAdapter(city)
+		file, _ := cmd.Flags().GetString("file")
+
+		names := mustNaming(city)
+		version, aliases, from := loadAliasFile(names, file)
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		cityID, err := dbClient.GetCityID(cityAdapter.Code(), cityAdapter.Name())
+		if err != nil {
+			fatalf("Failed to get city ID: %v", err)
+		}
+
+		result, err := naming.ImportAliases(dbClient, cityID, aliases, names.Normalizer)
+		if err != nil {
+			fatalf("Failed to import aliases: %v", err)
+		}
+
+		for _, u := range result.Unresolved {
+			fmt.Printf("Warning: Skipped %q -> %q: %s\n", u.Name, u.Station, u.Reason)
+		}
+		fmt.Printf("✅ Imported %d aliases from %s (version %d): %d new, %d already stored, %d skipped\n",
+			len(aliases), from, version, result.Added, result.Existing, len(result.Unresolved))
+	},
+}
+
+var aliasesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the stored aliases as CSV in the aliases.csv format",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		cityAdapter := mustAdapter(city)
+		file, _ := cmd.Flags().GetString("file")
+
+		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer dbClient.Close()
+
+		cityID, err := dbClient.GetCityID(cityAdapter.Code(), cityAdapter.Name())
+		if err != nil {
+			fatalf("Failed to get city ID: %v", err)
+		}
+		records, err := dbClient.GetStationAliasRecords(cityID)
+		if err != nil {
+			fatalf("Failed to get aliases: %v", err)
+		}
+
+		aliases := make([]naming.Alias, len(records))
+		for i, r := range records {
+			aliases[i] = naming.Alias{Name: r.AliasName, Station: r.StationName}
+		}
+
+		// Continue the city file's versioning, bumping it when the aliases differ
+		version := 1
+		if names, err := naming.Load(city); err == nil && names.Version(naming.AliasesFile) > 0 {
+			version = names.Version(naming.AliasesFile)
+			if !naming.SameAliases(names.Aliases, aliases) {
+				version++
+			}
+		}
+
+		out := os.Stdout
+		if file != "" {
+			out, err = os.Create(file)
+			if err != nil {
+				fatalf("Failed to create %s: %v", file, err)
+			}
+		}
+		if err := naming.WriteAliases(out, version, aliases); err != nil {
+			fatalf("Failed to write aliases: %v", err)
+		}
+		if file != "" {
+			if err := out.Close(); err != nil {
+				fatalf("Failed to write %s: %v", file, err)
+			}
+			fmt.Printf("✅ Exported %d aliases to %s (version %d)\n", len(aliases), file, version)
+		}
+	},
+}
+
+var aliasesRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Recompute every stored alias's normalized form with the city's current rules",
+	Long: `Re-normalizes every StationAlias of the city, for example after its normalization
+rules changed. Normalized aliases that would then refer to more than one station are
+reported; if any are new, nothing is written unless --resolve says how to handle them:
+
+  keep  write every change anyway (the colliding names are left ambiguous)
+  skip  leave the aliases involved in new collisions at their old normalized form`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		cityAdapter := mustAdapter(city)
+		resolve, _ := cmd.Flags().GetString("resolve")
+		if resolve != "" && resolve != "keep" && resolve != "skip" {
+			fatalf("Invalid --resolve %q (want keep or skip)", resolve)
+		}
+		names := mustNaming(city)
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		cityID, err := dbClient.GetCityID(cityAdapter.Code(), cityAdapter.Name())
+		if err != nil {
+			fatalf("Failed to get city ID: %v", err)
+		}
+		records, err := dbClient.GetStationAliasRecords(cityID)
+		if err != nil {
+			fatalf("Failed to get aliases: %v", err)
+		}
+
+		plan := naming.PlanRebuild(records, names.Normalizer)
+		fmt.Printf("Recomputed %d aliases: %d changed, %d unchanged\n",
+			len(records), len(plan.Changes), plan.Unchanged)
+		for _, c := range plan.Changes {
+			fmt.Printf("  %q (%s): %q -> %q\n", c.AliasName, c.StationName, c.Normalized, c.NewNormalized)
+		}
+
+		newCollisions := make(map[string]bool)
+		if len(plan.Collisions) > 0 {
+			fmt.Printf("⚠️  %d normalized aliases would refer to more than one station:\n", len(plan.Collisions))
+			printCollisions(plan.Collisions)
+			for _, c := range plan.Collisions {
+				if c.New {
+					newCollisions[c.Normalized] = true
+				}
+			}
+		}
+		if len(newCollisions) > 0 && resolve == "" {
+			fatalf("Not rebuilding: %d new collisions. Fix the rules or aliases, or re-run with --resolve=keep or --resolve=skip",
+				len(newCollisions))
+		}
+
+		updates := make(map[string]string, len(plan.Changes))
+		skipped := 0
+		for _, c := range plan.Changes {
+			if resolve == "skip" && newCollisions[c.NewNormalized] {
+				skipped++
+				continue
+			}
+			updates[c.ID] = c.NewNormalized
+		}
+		if len(updates) == 0 {
+			fmt.Printf("✅ Nothing to rebuild (%d changes skipped)\n", skipped)
+			return
+		}
+
+		if err := dbClient.UpdateStationAliasNormalized(updates); err != nil {
+			fatalf("Failed to rebuild aliases: %v", err)
+		}
+		fmt.Printf("✅ Rebuilt %d aliases (%d skipped because of collisions)\n", len(updates), skipped)
+	},
+}
+
+var aliasesCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Audit stored aliases for names shared by several stations and stale normalized forms",
+	Long: `Lists normalized aliases that refer to more than one station. Ridership names with
+such a form are not attributed to any station until the conflict is resolved. Also
+counts aliases whose normalized form is out of date with the city's rules (fixed by
+aliases rebuild). Exits with status 1 when there are conflicts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		cityAdapter := mustAdapter(city)
+		names := mustNaming(city)
+
+		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer dbClient.Close()
+
+		cityID, err := dbClient.GetCityID(cityAdapter.Code(), cityAdapter.Name())
+		if err != nil {
+			fatalf("Failed to get city ID: %v", err)
+		}
+		records, err := dbClient.GetStationAliasRecords(cityID)
+		if err != nil {
+			fatalf("Failed to get aliases: %v", err)
+		}
+
+		collisions := naming.FindCollisions(records)
+		stale := naming.PlanRebuild(records, names.Normalizer).Changes
+
+		fmt.Printf("Checked %d aliases\n", len(records))
+		if len(stale) > 0 {
+			fmt.Printf("⚠️  %d aliases are normalized with outdated rules; run aliases rebuild\n", len(stale))
+		}
+		if len(collisions) == 0 {
+			fmt.Println("✅ No alias refers to more than one station")
+			return
+		}
+		fmt.Printf("⚠️  %d normalized aliases refer to more than one station:\n", len(collisions))
+		printCollisions(collisions)
+		os.Exit(1)
+	},
+}
+
+// printCollisions lists each shared normalized alias with the stations and alias
+// names behind it
+func printCollisions(collisions []naming.AliasCollision) {
+	for _, c := range collisions {
+		label := ""
+		if c.New {
+			label = " (new)"
+		}
+		fmt.Printf("  %q%s:\n", c.Normalized, label)
+		for _, a := range c.Aliases {
+			fmt.Printf("    %-40s alias %q\n", a.StationName, a.AliasName)
+		}
+	}
+}
+
+// loadAliasFile reads aliases from file, or from the city's naming data when file
+// is empty, returning their version and where they came from
+func loadAliasFile(names *naming.Data, file string) (int, []naming.Alias, string) {
+	if file == "" {
+		return names.Version(naming.AliasesFile), names.Aliases, names.Source + "/" + naming.AliasesFile
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		fatalf("Failed to open %s: %v", file, err)
+	}
+	defer f.Close()
+
+	version, aliases, err := naming.ReadAliases(f)
+	if err != nil {
+		fatalf("Invalid aliases file %s: %v", file, err)
+	}
+	return version, aliases, file
+}
+
+func init() {
+	for _, c := range []*cobra.Command{aliasesImportCmd, aliasesExportCmd, aliasesRebuildCmd, aliasesCheckCmd} {
+		c.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	}
+	aliasesImportCmd.Flags().String("file", "", "Aliases CSV to import (default: the city's naming data)")
+	aliasesExportCmd.Flags().String("file", "", "Write to this file instead of stdout")
+	aliasesRebuildCmd.Flags().String("resolve", "", "How to handle new collisions: keep or skip (default: report them and stop)")
+
+	aliasesCmd.AddCommand(aliasesImportCmd, aliasesExportCmd, aliasesRebuildCmd, aliasesCheckCmd)
+	rootCmd.AddCommand(aliasesCmd)
+}
@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/nate/ghost-stops/go-etl/internal/chicago"
+	"github.com/nate/ghost-stops/go-etl/internal/adapter"
+	"github.com/nate/ghost-stops/go-etl/internal/anomaly"
 	"github.com/nate/ghost-stops/go-etl/internal/compute"
 	"github.com/nate/ghost-stops/go-etl/internal/db"
+	gtfsfeed "github.com/nate/ghost-stops/go-etl/internal/gtfs"
+	"github.com/nate/ghost-stops/go-etl/internal/socrata"
+
+	// City adapters register themselves with the adapter registry
+	_ "github.com/nate/ghost-stops/go-etl/internal/chicago"
 )
 
 var (
@@ -16,6 +27,18 @@ var (
 	source string
 	gtfs   string
 	ridership string
+	modelPath string
+	stationName string
+	historyRetention = db.DefaultHistoryRetention
+	baselineYear int
+	dryRun       bool
+	gtfsOpts     = adapter.GTFSOpts{RelocateMeters: gtfsfeed.DefaultRelocateMeters}
+	ingestOpts   adapter.IngestOpts
+
+	// dryRunDB is the scratch copy commands write to under --dry-run
+	dryRunDB *db.DryRun
+	// openClient is the client openDatabase returned, until closeDatabase closes it
+	openClient *db.Client
 )
 
 var rootCmd = &cobra.Command{
@@ -29,25 +52,21 @@ var gtfsCmd = &cobra.Command{
 	Short: "Ingest GTFS data for a city",
 	Run: func(cmd *cobra.Command, args []string) {
 		if city == "" || source == "" {
-			log.Fatal("--city and --source are required")
+			fatal("--city and --source are required")
 		}
+		cityAdapter := mustAdapter(city)
 
-		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		dbClient, err := openDatabase()
 		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+			fatalf("Failed to connect to database: %v", err)
 		}
-		defer dbClient.Close()
+		defer closeDatabase(dbClient)
 
-		switch city {
-		case "chicago":
-			err = chicago.IngestGTFS(dbClient, source)
-			if err != nil {
-				log.Fatalf("Failed to ingest Chicago GTFS: %v", err)
-			}
-			fmt.Println("✅ Chicago GTFS data ingested successfully")
-		default:
-			log.Fatalf("Unsupported city: %s", city)
+		err = cityAdapter.IngestGTFS(dbClient, source, gtfsOpts)
+		if err != nil {
+			fatalf("Failed to ingest %s GTFS: %v", cityAdapter.Name(), err)
 		}
+		fmt.Printf("✅ %s GTFS data ingested successfully\n", cityAdapter.Name())
 	},
 }
 
@@ -56,25 +75,26 @@ var ridershipCmd = &cobra.Command{
 	Short: "Ingest ridership data for a city",
 	Run: func(cmd *cobra.Command, args []string) {
 		if city == "" || source == "" {
-			log.Fatal("--city and --source are required")
+			fatal("--city and --source are required")
 		}
+		cityAdapter := mustAdapter(city)
 
-		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		dbClient, err := openDatabase()
 		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+			fatalf("Failed to connect to database: %v", err)
 		}
-		defer dbClient.Close()
+		defer closeDatabase(dbClient)
 
-		switch city {
-		case "chicago":
-			err = chicago.IngestRidership(dbClient, source)
-			if err != nil {
-				log.Fatalf("Failed to ingest Chicago ridership: %v", err)
-			}
-			fmt.Println("✅ Chicago ridership data ingested successfully")
-		default:
-			log.Fatalf("Unsupported city: %s", city)
+		// Ctrl-C stops the load after the batches already written
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ingestOpts.DryRun = dryRun
+		err = cityAdapter.IngestRidership(ctx, dbClient, source, ingestOpts)
+		if err != nil {
+			fatalf("Failed to ingest %s ridership: %v", cityAdapter.Name(), err)
 		}
+		fmt.Printf("✅ %s ridership data ingested successfully\n", cityAdapter.Name())
 	},
 }
 
@@ -83,25 +103,23 @@ var computeCmd = &cobra.Command{
 	Short: "Compute ghost scores and metrics for a city",
 	Run: func(cmd *cobra.Command, args []string) {
 		if city == "" {
-			log.Fatal("--city is required")
+			fatal("--city is required")
 		}
+		cityAdapter := mustAdapter(city)
 
-		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		dbClient, err := openDatabase()
 		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+			fatalf("Failed to connect to database: %v", err)
 		}
-		defer dbClient.Close()
+		defer closeDatabase(dbClient)
 
-		switch city {
-		case "chicago":
-			err = compute.ComputeGhostScores(dbClient, "chicago")
-			if err != nil {
-				log.Fatalf("Failed to compute ghost scores: %v", err)
-			}
-			fmt.Println("✅ Ghost scores computed successfully")
-		default:
-			log.Fatalf("Unsupported city: %s", city)
+		err = compute.ComputeGhostScores(dbClient, cityAdapter.Code(), computeOptions())
+		if err != nil {
+			fatalf("Failed to compute ghost scores: %v", err)
 		}
+		fmt.Println("✅ Ghost scores computed successfully")
+
+		pruneHistory(dbClient, cityAdapter.Code(), historyRetention)
 	},
 }
 
@@ -110,42 +128,43 @@ var allCmd = &cobra.Command{
 	Short: "Run all ETL steps for a city",
 	Run: func(cmd *cobra.Command, args []string) {
 		if city == "" || gtfs == "" || ridership == "" {
-			log.Fatal("--city, --gtfs, and --ridership are required")
+			fatal("--city, --gtfs, and --ridership are required")
 		}
+		cityAdapter := mustAdapter(city)
 
-		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		dbClient, err := openDatabase()
 		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+			fatalf("Failed to connect to database: %v", err)
 		}
-		defer dbClient.Close()
+		defer closeDatabase(dbClient)
 
-		switch city {
-		case "chicago":
-			// Step 1: Ingest GTFS
-			fmt.Println("📍 Ingesting Chicago GTFS data...")
-			err = chicago.IngestGTFS(dbClient, gtfs)
-			if err != nil {
-				log.Fatalf("Failed to ingest Chicago GTFS: %v", err)
-			}
+		// Step 1: Ingest GTFS
+		fmt.Printf("📍 Ingesting %s GTFS data...\n", cityAdapter.Name())
+		err = cityAdapter.IngestGTFS(dbClient, gtfs, gtfsOpts)
+		if err != nil {
+			fatalf("Failed to ingest %s GTFS: %v", cityAdapter.Name(), err)
+		}
 
-			// Step 2: Ingest ridership
-			fmt.Println("📊 Ingesting Chicago ridership data...")
-			err = chicago.IngestRidership(dbClient, ridership)
-			if err != nil {
-				log.Fatalf("Failed to ingest Chicago ridership: %v", err)
-			}
+		// Step 2: Ingest ridership
+		fmt.Printf("📊 Ingesting %s ridership data...\n", cityAdapter.Name())
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-			// Step 3: Compute ghost scores
-			fmt.Println("👻 Computing ghost scores...")
-			err = compute.ComputeGhostScores(dbClient, "chicago")
-			if err != nil {
-				log.Fatalf("Failed to compute ghost scores: %v", err)
-			}
+		ingestOpts.DryRun = dryRun
+		err = cityAdapter.IngestRidership(ctx, dbClient, ridership, ingestOpts)
+		if err != nil {
+			fatalf("Failed to ingest %s ridership: %v", cityAdapter.Name(), err)
+		}
 
-			fmt.Println("✅ All ETL steps completed successfully!")
-		default:
-			log.Fatalf("Unsupported city: %s", city)
+		// Step 3: Compute ghost scores
+		fmt.Println("👻 Computing ghost scores...")
+		err = compute.ComputeGhostScores(dbClient, cityAdapter.Code(), compute.DefaultOptions())
+		if err != nil {
+			fatalf("Failed to compute ghost scores: %v", err)
 		}
+		pruneHistory(dbClient, cityAdapter.Code(), db.DefaultHistoryRetention)
+
+		fmt.Println("✅ All ETL steps completed successfully!")
 	},
 }
 
@@ -155,72 +174,265 @@ var syncRidershipCmd = &cobra.Command{
 	Short: "Syncs CTA ridership data from the Socrata API",
 	Run: func(cmd *cobra.Command, args []string) {
 		if city == "" {
-			log.Fatal("--city is required")
+			fatal("--city is required")
 		}
+		cityAdapter := mustAdapter(city)
 
 		databaseURL := os.Getenv("DATABASE_URL")
 		if databaseURL == "" {
-			log.Fatal("DATABASE_URL environment variable is required")
+			fatal("DATABASE_URL environment variable is required")
 		}
 
-		appToken := os.Getenv("CHICAGO_DATA_APP_TOKEN")
+		appToken := socrata.AppToken()
 		if appToken == "" {
-			log.Println("Warning: CHICAGO_DATA_APP_TOKEN not set, proceeding without app token")
+			log.Printf("Warning: %s not set, proceeding without app token", socrata.AppTokenEnv)
 		}
 
-		dbClient, err := db.NewClient(databaseURL)
+		dbClient, err := openDatabase()
 		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+			fatalf("Failed to connect to database: %v", err)
 		}
-		defer dbClient.Close()
+		defer closeDatabase(dbClient)
 
 		days, _ := cmd.Flags().GetInt("days")
 		since, _ := cmd.Flags().GetString("since")
 		limit, _ := cmd.Flags().GetInt("limit")
+		restart, _ := cmd.Flags().GetBool("restart")
+		lookback, _ := cmd.Flags().GetInt("lookback-days")
+		noUpdatedAt, _ := cmd.Flags().GetBool("no-updated-at")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+		fixtureDir, _ := cmd.Flags().GetString("fixture-dir")
+		record, _ := cmd.Flags().GetBool("record")
+		matchThreshold, _ := cmd.Flags().GetFloat64("match-threshold")
+		matchRadius, _ := cmd.Flags().GetFloat64("match-radius")
+
+		opts := adapter.SyncOpts{
+			Days:         days,
+			Since:        since,
+			Limit:        limit,
+			Restart:      restart,
+			LookbackDays: lookback,
+			NoUpdatedAt:  noUpdatedAt,
+			BaseURL:      baseURL,
+			FixtureDir:   fixtureDir,
+			Record:       record,
+
+			MatchThreshold:    matchThreshold,
+			MatchRadiusMeters: matchRadius,
+
+			DryRun: dryRun,
+		}
+
+		err = cityAdapter.SyncRidership(dbClient, appToken, opts)
+		if err != nil {
+			fatalf("Failed to sync %s ridership: %v", cityAdapter.Name(), err)
+		}
+		fmt.Printf("✅ %s ridership data synced successfully\n", cityAdapter.Name())
+
+		// Check the freshly synced data for closures and upstream glitches
+		if _, err := anomaly.Run(dbClient, cityAdapter.Code(), anomaly.DefaultOptions()); err != nil {
+			log.Printf("Warning: Anomaly detection failed: %v", err)
+		}
+	},
+}
+
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Show how a station's latest ghost score was derived",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" || stationName == "" {
+			fatal("--city and --station are required")
+		}
 
-		opts := chicago.SyncOpts{
-			Days:  days,
-			Since: since,
-			Limit: limit,
+		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
 		}
+		defer dbClient.Close()
+
+		normalized := mustNaming(city).Normalizer.Normalize(stationName)
+		explanations, err := dbClient.GetScoreExplanations(city, stationName, normalized)
+		if err != nil {
+			fatalf("Failed to get score explanation: %v", err)
+		}
+		if len(explanations) == 0 {
+			fatalf("No score explanation found for %q in %s (run compute first)", stationName, city)
+		}
+
+		for _, e := range explanations {
+			if err := compute.PrintExplanation(os.Stdout, e); err != nil {
+				fatalf("Failed to print explanation: %v", err)
+			}
 
-		switch city {
-		case "chicago":
-			err := chicago.SyncRidership(dbClient, appToken, opts)
+			dayMetrics, err := dbClient.GetStationDayTypeMetrics(e.StationID)
 			if err != nil {
-				log.Fatalf("Failed to sync Chicago ridership: %v", err)
+				fatalf("Failed to get day type metrics: %v", err)
 			}
-			fmt.Println("✅ Chicago ridership data synced successfully")
-		default:
-			log.Fatalf("Unsupported city: %s", city)
+			compute.PrintDayTypeMetrics(os.Stdout, dayMetrics)
 		}
 	},
 }
 
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show a station's ghost score history",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" || stationName == "" {
+			fatal("--city and --station are required")
+		}
+
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+
+		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer dbClient.Close()
+
+		normalized := mustNaming(city).Normalizer.Normalize(stationName)
+		stations, err := dbClient.FindStations(city, stationName, normalized)
+		if err != nil {
+			fatalf("Failed to find station: %v", err)
+		}
+		if len(stations) == 0 {
+			fatalf("No station found for %q in %s", stationName, city)
+		}
+
+		for _, station := range stations {
+			snapshots, err := dbClient.GetMetricsHistory(station.ID, from, to)
+			if err != nil {
+				fatalf("Failed to get history: %v", err)
+			}
+
+			fmt.Printf("\n%s (%d snapshots)\n", station.Name, len(snapshots))
+			fmt.Printf("%-12s %6s %10s %10s %-8s %s\n", "Date", "Score", "30d avg", "90d avg", "Status", "Model")
+			for _, s := range snapshots {
+				score := strconv.Itoa(s.GhostScore)
+				if s.GhostScore < 0 {
+					score = "-"
+				}
+				fmt.Printf("%-12s %6s %10.0f %10.0f %-8s %s\n",
+					s.SnapshotDate, score, s.Rolling30dAvg, s.Rolling90dAvg, s.DataStatus, s.ModelVersion)
+			}
+		}
+	},
+}
+
+var backfillScoresCmd = &cobra.Command{
+	Use:   "backfill-scores",
+	Short: "Recompute ghost scores as of past dates into the history table",
+	Run: func(cmd *cobra.Command, args []string) {
+		fromStr, _ := cmd.Flags().GetString("from")
+		toStr, _ := cmd.Flags().GetString("to")
+		step, _ := cmd.Flags().GetString("step")
+
+		if city == "" || fromStr == "" || toStr == "" {
+			fatal("--city, --from, and --to are required")
+		}
+		cityAdapter := mustAdapter(city)
+
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			fatalf("Invalid --from date: %v", err)
+		}
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			fatalf("Invalid --to date: %v", err)
+		}
+
+		opts := computeOptions()
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		err = compute.BackfillScores(dbClient, cityAdapter.Code(), opts, from, to, step)
+		if err != nil {
+			fatalf("Failed to backfill ghost scores: %v", err)
+		}
+		fmt.Println("✅ Ghost score backfill completed successfully")
+	},
+}
+
+var anomaliesCmd = &cobra.Command{
+	Use:   "anomalies",
+	Short: "Report ridership drops and spikes against each station's weekday baseline",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		cityAdapter := mustAdapter(city)
+
+		since, _ := cmd.Flags().GetString("since")
+		minSeverity, _ := cmd.Flags().GetString("min-severity")
+		detect, _ := cmd.Flags().GetBool("detect")
+
+		if since == "" {
+			since = time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+		}
+		if anomaly.SeverityRank(minSeverity) == 0 {
+			fatalf("Invalid --min-severity %q (use low, medium or high)", minSeverity)
+		}
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		if detect {
+			if _, err := anomaly.Run(dbClient, cityAdapter.Code(), anomaly.DefaultOptions()); err != nil {
+				fatalf("Failed to detect anomalies: %v", err)
+			}
+		}
+
+		anomalies, err := dbClient.GetRidershipAnomalies(cityAdapter.Code(), since)
+		if err != nil {
+			fatalf("Failed to get anomalies: %v", err)
+		}
+
+		fmt.Printf("Ridership anomalies for %s since %s (severity >= %s):\n\n", cityAdapter.Name(), since, minSeverity)
+		fmt.Printf("%-12s %-35s %-6s %-7s %9s %9s %7s\n", "Date", "Station", "Kind", "Sev", "Entries", "Expected", "Score")
+		shown := 0
+		for _, a := range anomalies {
+			if anomaly.SeverityRank(a.Severity) < anomaly.SeverityRank(minSeverity) {
+				continue
+			}
+			fmt.Printf("%-12s %-35s %-6s %-7s %9d %9.0f %7.1f\n",
+				a.ServiceDate, a.StationName, a.Kind, a.Severity, a.Entries, a.Expected, a.Score)
+			shown++
+		}
+		fmt.Printf("\n%d anomalies\n", shown)
+	},
+}
 
 var listStationsCmd = &cobra.Command{
 	Use:   "list-stations",
 	Short: "List all station names for a city",
 	Run: func(cmd *cobra.Command, args []string) {
 		if city == "" {
-			log.Fatal("--city is required")
+			fatal("--city is required")
 		}
 
 
 		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
 		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+			fatalf("Failed to connect to database: %v", err)
 		}
 		defer dbClient.Close()
 
 		cityID, err := dbClient.GetCityID(city, "")
 		if err != nil {
-			log.Fatalf("Failed to get city ID: %v", err)
+			fatalf("Failed to get city ID: %v", err)
 		}
 
 		names, err := dbClient.GetAllStationNames(cityID)
 		if err != nil {
-			log.Fatalf("Failed to get station names: %v", err)
+			fatalf("Failed to get station names: %v", err)
 		}
 
 		fmt.Printf("Stations for %s:\n", city)
@@ -230,22 +442,151 @@ var listStationsCmd = &cobra.Command{
 	},
 }
 
+// computeOptions builds scoring options from the --model and --baseline-year flags
+func computeOptions() compute.Options {
+	opts := compute.DefaultOptions()
+	opts.BaselineYear = baselineYear
+
+	if modelPath != "" {
+		model, err := compute.LoadModel(modelPath)
+		if err != nil {
+			fatalf("Failed to load scoring model: %v", err)
+		}
+		opts.Model = model
+	}
+	return opts
+}
+
+// pruneHistory applies the metrics history retention policy, warning on failure
+func pruneHistory(dbClient *db.Client, cityCode string, retention db.HistoryRetention) {
+	deleted, err := dbClient.PruneMetricsHistory(cityCode, retention)
+	if err != nil {
+		log.Printf("Warning: Failed to prune metrics history: %v", err)
+		return
+	}
+	if deleted > 0 {
+		fmt.Printf("🧹 Pruned %d metrics history snapshots\n", deleted)
+	}
+}
+
+// openDatabase connects to DATABASE_URL, or under --dry-run to a scratch copy of it
+func openDatabase() (*db.Client, error) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if !dryRun {
+		client, err := db.NewClient(databaseURL)
+		openClient = client
+		return client, err
+	}
+
+	var err error
+	dryRunDB, err = db.OpenDryRun(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("🧪 Dry run: changes will be reported and discarded")
+	openClient = dryRunDB.Client
+	return dryRunDB.Client, nil
+}
+
+// closeDatabase disconnects; under --dry-run it first reports what the command
+// changed and then discards the copy
+func closeDatabase(dbClient *db.Client) {
+	if dbClient == nil || dbClient != openClient {
+		return // Already closed
+	}
+	openClient = nil
+	if dryRunDB == nil {
+		dbClient.Close()
+		return
+	}
+	defer func() {
+		dryRunDB.Close()
+		dryRunDB = nil
+	}()
+
+	diff, err := dryRunDB.Diff()
+	if err != nil {
+		log.Printf("Warning: Failed to compare dry-run changes: %v", err)
+		return
+	}
+	diff.Print(os.Stdout, 20)
+}
+
+// fatalf logs like log.Fatalf and exits, first closing the database from
+// openDatabase: os.Exit skips deferred calls, and a dry run still needs to report
+// its changes and delete its scratch copy
+func fatalf(format string, v ...interface{}) {
+	closeDatabase(openClient)
+	log.Fatalf(format, v...)
+}
+
+// fatal is fatalf for log.Fatal
+func fatal(v ...interface{}) {
+	closeDatabase(openClient)
+	log.Fatal(v...)
+}
+
+// mustAdapter looks up the registered adapter for a city code or exits
+func mustAdapter(code string) adapter.CityAdapter {
+	a, err := adapter.Get(code)
+	if err != nil {
+		fatal(err)
+	}
+	return a
+}
+
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Run against a scratch copy of the database and report what would change")
+
 	// GTFS command flags
 	gtfsCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
 	gtfsCmd.Flags().StringVar(&source, "source", "", "GTFS data source (URL or local file)")
+	gtfsCmd.Flags().Float64Var(&gtfsOpts.RelocateMeters, "relocate-meters", gtfsfeed.DefaultRelocateMeters, "Report stations that moved further than this between feeds")
+	gtfsCmd.Flags().BoolVar(&gtfsOpts.DeactivateRemoved, "deactivate-removed", false, "Mark stations missing from the feed inactive")
 
 	// Ridership command flags
 	ridershipCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
 	ridershipCmd.Flags().StringVar(&source, "source", "", "Ridership data source (URL or local file)")
+	ridershipCmd.Flags().IntVar(&ingestOpts.Workers, "workers", 0, "Goroutines parsing and matching rows (default: one per CPU)")
 
 	// Compute command flags
 	computeCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	computeCmd.Flags().StringVar(&modelPath, "model", "", "Scoring model JSON file (default: 30-day average percentile)")
+	computeCmd.Flags().IntVar(&baselineYear, "baseline-year", compute.DefaultBaselineYear, "Year recovery ratios compare against (0 to skip)")
+	computeCmd.Flags().IntVar(&historyRetention.DailyDays, "history-daily-days", db.DefaultHistoryRetention.DailyDays, "Keep daily score history this many days, then thin to weekly (0 = never thin)")
+	computeCmd.Flags().IntVar(&historyRetention.MaxDays, "history-max-days", db.DefaultHistoryRetention.MaxDays, "Delete score history older than this many days (0 = keep forever)")
 
 	// All command flags
 	allCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
 	allCmd.Flags().StringVar(&gtfs, "gtfs", "", "GTFS data source (URL or local file)")
 	allCmd.Flags().StringVar(&ridership, "ridership", "", "Ridership data source (URL or local file)")
+	allCmd.Flags().IntVar(&ingestOpts.Workers, "workers", 0, "Goroutines parsing and matching ridership rows (default: one per CPU)")
+	allCmd.Flags().Float64Var(&gtfsOpts.RelocateMeters, "relocate-meters", gtfsfeed.DefaultRelocateMeters, "Report stations that moved further than this between feeds")
+	allCmd.Flags().BoolVar(&gtfsOpts.DeactivateRemoved, "deactivate-removed", false, "Mark stations missing from the feed inactive")
+
+	// Explain command flags
+	explainCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	explainCmd.Flags().StringVar(&stationName, "station", "", "Station name or alias (e.g., \"Clark/Lake\")")
+
+	// History command flags
+	historyCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	historyCmd.Flags().StringVar(&stationName, "station", "", "Station name or alias (e.g., \"Clark/Lake\")")
+	historyCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	historyCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+
+	// Backfill scores command flags
+	backfillScoresCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	backfillScoresCmd.Flags().String("from", "", "First as-of date (YYYY-MM-DD)")
+	backfillScoresCmd.Flags().String("to", "", "Last as-of date (YYYY-MM-DD)")
+	backfillScoresCmd.Flags().String("step", compute.StepWeek, "Interval between as-of dates: day, week or month")
+	backfillScoresCmd.Flags().StringVar(&modelPath, "model", "", "Scoring model JSON file (default: 30-day average percentile)")
+	backfillScoresCmd.Flags().IntVar(&baselineYear, "baseline-year", compute.DefaultBaselineYear, "Year recovery ratios compare against (0 to skip)")
+
+	// Anomalies command flags
+	anomaliesCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	anomaliesCmd.Flags().String("since", "", "Earliest service date to report (YYYY-MM-DD, default 30 days ago)")
+	anomaliesCmd.Flags().String("min-severity", anomaly.SeverityLow, "Minimum severity to report: low, medium or high")
+	anomaliesCmd.Flags().Bool("detect", false, "Re-run detection before reporting")
 
 	// List stations command flags
 	listStationsCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
@@ -255,6 +596,14 @@ func init() {
 	syncRidershipCmd.Flags().Int("days", 365, "Retention period in days")
 	syncRidershipCmd.Flags().String("since", "", "Override start date (YYYY-MM-DD)")
 	syncRidershipCmd.Flags().Int("limit", 50000, "Socrata page size")
+	syncRidershipCmd.Flags().Bool("restart", false, "Ignore the checkpoint of an interrupted sync and start over")
+	syncRidershipCmd.Flags().Int("lookback-days", 0, "Also re-fetch this many days before the latest stored date")
+	syncRidershipCmd.Flags().Bool("no-updated-at", false, "Don't re-fetch rows revised upstream since the last sync (:updated_at; implied by --since and --fixture-dir)")
+	syncRidershipCmd.Flags().String("base-url", "", "Fetch from this Socrata host instead of the live API (e.g. http://localhost:8080)")
+	syncRidershipCmd.Flags().String("fixture-dir", "", "Replay recorded Socrata responses from this directory instead of the network")
+	syncRidershipCmd.Flags().Bool("record", false, "With --fixture-dir, fetch normally and record each response")
+	syncRidershipCmd.Flags().Float64("match-threshold", 0, "Minimum fuzzy station match confidence, 0-1 (default: the city's matcher default)")
+	syncRidershipCmd.Flags().Float64("match-radius", 0, "Meters within which a station counts as near a source's coordinates (default: the city's matcher default)")
 
 	// Add commands to root
 	rootCmd.AddCommand(gtfsCmd)
@@ -263,6 +612,10 @@ func init() {
 	rootCmd.AddCommand(allCmd)
 	rootCmd.AddCommand(listStationsCmd)
 	rootCmd.AddCommand(syncRidershipCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(backfillScoresCmd)
+	rootCmd.AddCommand(anomaliesCmd)
 }
 
 func main() {
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/nate/ghost-stops/go-etl/internal/naming"
+)
+
+var normalizeCmd = &cobra.Command{
+	Use:   "normalize <name>...",
+	Short: "Show how a city's normalization rules transform station names",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		names := mustNaming(city)
+
+		source := names.Source + "/" + naming.NormalizationFile
+		if names.Version(naming.NormalizationFile) == 0 {
+			source = "default rules"
+		}
+		fmt.Printf("%s normalization (%s)\n", city, source)
+
+		for _, name := range args {
+			fmt.Printf("\n%-16s %q\n", "input", name)
+			previous := name
+			for _, step := range names.Normalizer.Trace(name) {
+				if step.Output == previous {
+					fmt.Printf("%-16s (unchanged)\n", step.Rule)
+				} else {
+					fmt.Printf("%-16s %q\n", step.Rule, step.Output)
+				}
+				previous = step.Output
+			}
+			fmt.Printf("%-16s %q\n", "result", previous)
+		}
+	},
+}
+
+// mustNaming loads a city's naming data, including its compiled normalizer, or exits
+func mustNaming(cityCode string) *naming.Data {
+	names, err := naming.Load(cityCode)
+	if err != nil {
+		fatalf("Failed to load naming data: %v", err)
+	}
+	return names
+}
+
+func init() {
+	normalizeCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+
+	rootCmd.AddCommand(normalizeCmd)
+}
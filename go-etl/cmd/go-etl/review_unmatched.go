@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/nate/ghost-stops/go-etl/internal/adapter"
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/socrata"
+)
+
+// unmatchedEntry is one row of the unmatched stations report
+type unmatchedEntry struct {
+	SourceStationID string
+	Name            string
+	Reason          string
+	Occurrences     int
+	Location        *adapter.Location // Where the source placed the station, if anywhere
+}
+
+var reviewUnmatchedCmd = &cobra.Command{
+	Use:   "review-unmatched",
+	Short: "Interactively map unmatched ridership station names to stations",
+	Long: `Walks the unmatched stations report written by sync-ridership (or the markdown
+report of the ridership CSV load), most frequent first, showing the closest stations
+for each name. A confirmed choice is stored as a StationAlias (and the source station
+ID where the station has none), so later syncs match it. With --resync the affected
+stations' ridership is fetched again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+		cityAdapter := mustAdapter(city)
+
+		reportPath, _ := cmd.Flags().GetString("report")
+		n, _ := cmd.Flags().GetInt("candidates")
+		resync, _ := cmd.Flags().GetBool("resync")
+		days, _ := cmd.Flags().GetInt("days")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+		fixtureDir, _ := cmd.Flags().GetString("fixture-dir")
+
+		entries, err := readUnmatchedReport(reportPath)
+		if err != nil {
+			fatalf("Failed to read unmatched report: %v", err)
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No unmatched stations in %s\n", reportPath)
+			return
+		}
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		cityID, err := dbClient.GetCityID(cityAdapter.Code(), cityAdapter.Name())
+		if err != nil {
+			fatalf("Failed to get city ID: %v", err)
+		}
+		matcher, err := cityAdapter.NewStationMatcher(dbClient, cityID)
+		if err != nil {
+			fatalf("Failed to create station matcher: %v", err)
+		}
+
+		in := bufio.NewReader(os.Stdin)
+		var mapped []string // Source station IDs that now have a mapping
+		reviewed := 0
+	review:
+		for i, e := range entries {
+			fmt.Printf("\n[%d/%d] %q", i+1, len(entries), e.Name)
+			if e.SourceStationID != "" {
+				fmt.Printf(" (station ID %s)", e.SourceStationID)
+			}
+			fmt.Printf(", %d rows\n", e.Occurrences)
+			if e.Reason != "" {
+				fmt.Printf("Reason: %s\n", e.Reason)
+			}
+
+			station, action := chooseStation(in, dbClient, cityAdapter.Code(), matcher.Candidates(e.Name, e.Location, n))
+			switch action {
+			case reviewQuit:
+				break review
+			case reviewSkip:
+				continue
+			}
+			if err := matcher.Confirm(e.SourceStationID, e.Name, station.ID); err != nil {
+				log.Printf("Warning: Failed to save mapping for %q: %v", e.Name, err)
+				continue
+			}
+			reviewed++
+			fmt.Printf("✅ %q now maps to %s\n", e.Name, station.Name)
+			if e.SourceStationID != "" {
+				mapped = append(mapped, e.SourceStationID)
+			}
+		}
+
+		fmt.Printf("\nMapped %d of %d unmatched names\n", reviewed, len(entries))
+
+		if !resync || len(mapped) == 0 {
+			switch {
+			case len(mapped) > 0:
+				fmt.Println("Run review-unmatched --resync or sync-ridership --since=<date> to load their ridership")
+			case reviewed > 0:
+				fmt.Println("Re-run the ridership load to pick up their rows")
+			}
+			return
+		}
+
+		appToken := socrata.AppToken()
+		opts := adapter.SyncOpts{Days: days, BaseURL: baseURL, FixtureDir: fixtureDir, DryRun: dryRun}
+		if err := cityAdapter.ResyncStations(dbClient, appToken, mapped, opts); err != nil {
+			fatalf("Failed to re-fetch ridership for mapped stations: %v", err)
+		}
+		fmt.Printf("✅ Re-fetched ridership for %d stations\n", len(mapped))
+	},
+}
+
+type reviewAction int
+
+const (
+	reviewMap reviewAction = iota
+	reviewSkip
+	reviewQuit
+)
+
+// chooseStation prompts until a station is picked and confirmed, or the name is
+// skipped or the review quit. Entering other text searches station names.
+func chooseStation(in *bufio.Reader, dbClient *db.Client, cityCode string, candidates []adapter.Candidate) (db.StationRef, reviewAction) {
+	scored := true
+	for {
+		for i, c := range candidates {
+			if scored && c.DistanceMeters >= 0 {
+				fmt.Printf("  %d. %-45s %.2f  %.0fm away\n", i+1, c.StationName, c.Score, c.DistanceMeters)
+			} else if scored {
+				fmt.Printf("  %d. %-45s %.2f\n", i+1, c.StationName, c.Score)
+			} else {
+				fmt.Printf("  %d. %s\n", i+1, c.StationName)
+			}
+		}
+		answer, ok := prompt(in, "Station number, name to search, s to skip, q to quit [s]: ")
+		if !ok || answer == "q" {
+			return db.StationRef{}, reviewQuit
+		}
+		if answer == "" || answer == "s" {
+			return db.StationRef{}, reviewSkip
+		}
+
+		if choice, err := strconv.Atoi(answer); err == nil {
+			if choice < 1 || choice > len(candidates) {
+				fmt.Printf("Choose 1-%d\n", len(candidates))
+				continue
+			}
+			station := db.StationRef{ID: candidates[choice-1].StationID, Name: candidates[choice-1].StationName}
+			confirm, ok := prompt(in, fmt.Sprintf("Map to %s? [Y/n]: ", station.Name))
+			if !ok {
+				return db.StationRef{}, reviewQuit
+			}
+			if confirm == "" || strings.EqualFold(confirm, "y") {
+				return station, reviewMap
+			}
+			continue
+		}
+
+		found, err := searchStations(dbClient, cityCode, answer)
+		if err != nil {
+			log.Printf("Warning: Station search failed: %v", err)
+			continue
+		}
+		if len(found) == 0 {
+			fmt.Printf("No station names contain %q\n", answer)
+			continue
+		}
+		candidates, scored = found, false
+	}
+}
+
+// searchStations lists stations whose name contains text, ignoring case
+func searchStations(dbClient *db.Client, cityCode, text string) ([]adapter.Candidate, error) {
+	rows, err := dbClient.Query(`
+		SELECT s.id, s.name
+		FROM Station s
+		JOIN City c ON c.id = s.cityId
+		WHERE c.code = ? AND instr(lower(s.name), lower(?)) > 0
+		ORDER BY s.name`,
+		cityCode, text,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var found []adapter.Candidate
+	for rows.Next() {
+		var c adapter.Candidate
+		if err := rows.Scan(&c.StationID, &c.StationName); err != nil {
+			return nil, err
+		}
+		found = append(found, c)
+	}
+	return found, rows.Err()
+}
+
+// prompt prints msg and reads one trimmed line, reporting false at end of input
+func prompt(in *bufio.Reader, msg string) (string, bool) {
+	fmt.Print(msg)
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		fmt.Println()
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// readUnmatchedReport reads the CSV written by sync-ridership, or the markdown
+// report written by ridership (.md), most frequent names first
+func readUnmatchedReport(path string) ([]unmatchedEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []unmatchedEntry
+	if strings.HasSuffix(path, ".md") {
+		entries, err = readMarkdownReport(f)
+	} else {
+		entries, err = readCSVReport(f, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Occurrences > entries[j].Occurrences
+	})
+	return entries, nil
+}
+
+// readMarkdownReport reads the "| Station Name | Occurrences |" table rows
+func readMarkdownReport(r io.Reader) ([]unmatchedEntry, error) {
+	var entries []unmatchedEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		cells := strings.Split(strings.Trim(strings.TrimSpace(scanner.Text()), "|"), "|")
+		if len(cells) != 2 {
+			continue
+		}
+		occurrences, err := strconv.Atoi(strings.TrimSpace(cells[1]))
+		if err != nil {
+			continue // Header and separator rows
+		}
+		entries = append(entries, unmatchedEntry{Name: strings.TrimSpace(cells[0]), Occurrences: occurrences})
+	}
+	return entries, scanner.Err()
+}
+
+// readCSVReport reads unmatched_socrata.csv by column name
+func readCSVReport(f io.Reader, path string) ([]unmatchedEntry, error) {
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int)
+	for i, name := range header {
+		col[name] = i
+	}
+	if _, ok := col["stationname"]; !ok {
+		return nil, fmt.Errorf("%s has no stationname column", path)
+	}
+	field := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	var entries []unmatchedEntry
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		occurrences, _ := strconv.Atoi(field(row, "occurrences"))
+		entry := unmatchedEntry{
+			SourceStationID: field(row, "station_id"),
+			Name:            field(row, "stationname"),
+			Reason:          field(row, "reason"),
+			Occurrences:     occurrences,
+		}
+		lat, latErr := strconv.ParseFloat(field(row, "latitude"), 64)
+		lon, lonErr := strconv.ParseFloat(field(row, "longitude"), 64)
+		if latErr == nil && lonErr == nil {
+			entry.Location = &adapter.Location{Latitude: lat, Longitude: lon}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func init() {
+	reviewUnmatchedCmd.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+	reviewUnmatchedCmd.Flags().String("report", "docs/unmatched_socrata.csv", "Unmatched stations report (sync-ridership CSV, or the ridership load's .md)")
+	reviewUnmatchedCmd.Flags().Int("candidates", 5, "Candidate stations to show per name")
+	reviewUnmatchedCmd.Flags().Bool("resync", false, "Re-fetch ridership for the station IDs that were mapped")
+	reviewUnmatchedCmd.Flags().Int("days", 365, "With --resync, how many days of ridership to re-fetch")
+	reviewUnmatchedCmd.Flags().String("base-url", "", "With --resync, fetch from this Socrata host instead of the live API")
+	reviewUnmatchedCmd.Flags().String("fixture-dir", "", "With --resync, replay recorded Socrata responses from this directory")
+
+	rootCmd.AddCommand(reviewUnmatchedCmd)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+var stationStatusCmd = &cobra.Command{
+	Use:   "station-status",
+	Short: "Manage station lifecycle status (open, temporarily or permanently closed)",
+	Long: `Record when stations close and reopen. Stations that are not open on the date
+a compute run's windows end are labeled closed and left out of ghost scoring.`,
+}
+
+var stationStatusSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Record a station status taking effect on a date",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" || stationName == "" {
+			fatal("--city and --station are required")
+		}
+
+		status, _ := cmd.Flags().GetString("status")
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		note, _ := cmd.Flags().GetString("note")
+
+		if !validStationStatus(status) {
+			fatalf("Invalid --status %q (want one of %v)", status, db.StationStatuses)
+		}
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			fatalf("Invalid --from date %q (want YYYY-MM-DD)", from)
+		}
+		if to != "" {
+			if _, err := time.Parse("2006-01-02", to); err != nil {
+				fatalf("Invalid --to date %q (want YYYY-MM-DD)", to)
+			}
+			if to < from {
+				fatal("--to must not be before --from")
+			}
+		}
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		station := mustFindStation(dbClient, city, stationName)
+		err = dbClient.SetStationStatus(db.StationStatus{
+			StationID:     station.ID,
+			Status:        status,
+			EffectiveFrom: from,
+			EffectiveTo:   to,
+			Note:          note,
+		})
+		if err != nil {
+			fatalf("Failed to set station status: %v", err)
+		}
+
+		until := "until further notice"
+		if to != "" {
+			until = "through " + to
+		}
+		fmt.Printf("✅ %s: %s from %s %s\n", station.Name, db.StationStatusName(status), from, until)
+	},
+}
+
+var stationStatusListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show station status history",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" {
+			fatal("--city is required")
+		}
+
+		dbClient, err := db.NewClient(os.Getenv("DATABASE_URL"))
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer dbClient.Close()
+
+		var stationID string
+		if stationName != "" {
+			stationID = mustFindStation(dbClient, city, stationName).ID
+		}
+
+		statuses, err := dbClient.GetStationStatuses(city, stationID)
+		if err != nil {
+			fatalf("Failed to get station statuses: %v", err)
+		}
+		if len(statuses) == 0 {
+			fmt.Println("No station status records (every station is treated as open)")
+			return
+		}
+
+		fmt.Printf("%-35s %-20s %-12s %-12s %s\n", "Station", "Status", "From", "To", "Note")
+		for _, s := range statuses {
+			to := s.EffectiveTo
+			if to == "" {
+				to = "-"
+			}
+			fmt.Printf("%-35s %-20s %-12s %-12s %s\n",
+				s.StationName, db.StationStatusName(s.Status), s.EffectiveFrom, to, s.Note)
+		}
+	},
+}
+
+var stationStatusDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete the status record that starts on a date",
+	Run: func(cmd *cobra.Command, args []string) {
+		if city == "" || stationName == "" {
+			fatal("--city and --station are required")
+		}
+		from, _ := cmd.Flags().GetString("from")
+
+		dbClient, err := openDatabase()
+		if err != nil {
+			fatalf("Failed to connect to database: %v", err)
+		}
+		defer closeDatabase(dbClient)
+
+		station := mustFindStation(dbClient, city, stationName)
+		deleted, err := dbClient.DeleteStationStatus(station.ID, from)
+		if err != nil {
+			fatalf("Failed to delete station status: %v", err)
+		}
+		if !deleted {
+			fatalf("No status record for %s starts on %s", station.Name, from)
+		}
+		fmt.Printf("✅ Deleted %s status record from %s\n", station.Name, from)
+	},
+}
+
+// validStationStatus reports whether status is a known lifecycle status
+func validStationStatus(status string) bool {
+	for _, s := range db.StationStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// mustFindStation resolves a station name or alias to exactly one station or exits
+func mustFindStation(dbClient *db.Client, cityCode, name string) db.StationRef {
+	stations, err := dbClient.FindStations(cityCode, name, mustNaming(cityCode).Normalizer.Normalize(name))
+	if err != nil {
+		fatalf("Failed to find station: %v", err)
+	}
+	if len(stations) == 0 {
+		fatalf("No station found for %q in %s", name, cityCode)
+	}
+	if len(stations) > 1 {
+		for _, s := range stations {
+			fmt.Printf("- %s (%s)\n", s.Name, s.ID)
+		}
+		fatalf("%q matches %d stations; use a more specific name", name, len(stations))
+	}
+	return stations[0]
+}
+
+func init() {
+	for _, c := range []*cobra.Command{stationStatusSetCmd, stationStatusListCmd, stationStatusDeleteCmd} {
+		c.Flags().StringVar(&city, "city", "", "City code (e.g., chicago)")
+		c.Flags().StringVar(&stationName, "station", "", "Station name or alias (e.g., \"Clark/Lake\")")
+	}
+	stationStatusSetCmd.Flags().String("status", "", "open, temporarily_closed or permanently_closed")
+	stationStatusSetCmd.Flags().String("from", "", "First day the status applies (YYYY-MM-DD)")
+	stationStatusSetCmd.Flags().String("to", "", "Last day the status applies (YYYY-MM-DD, default open-ended)")
+	stationStatusSetCmd.Flags().String("note", "", "Reason for the change (e.g., \"Red Line reconstruction\")")
+	stationStatusSetCmd.MarkFlagRequired("status")
+	stationStatusSetCmd.MarkFlagRequired("from")
+	stationStatusDeleteCmd.Flags().String("from", "", "Start date of the record to delete (YYYY-MM-DD)")
+	stationStatusDeleteCmd.MarkFlagRequired("from")
+
+	stationStatusCmd.AddCommand(stationStatusSetCmd, stationStatusListCmd, stationStatusDeleteCmd)
+	rootCmd.AddCommand(stationStatusCmd)
+}
@@ -0,0 +1,134 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// SyncOpts controls an incremental ridership sync
+type SyncOpts struct {
+	Days    int
+	Since   string
+	Limit   int
+	Restart bool // Ignore any checkpoint left by an interrupted sync
+
+	LookbackDays int  // Also re-fetch this many days before the latest stored date
+	NoUpdatedAt  bool // Don't use the source's last-modified field to find revised rows
+
+	BaseURL    string // Fetch from this host instead of the live API
+	FixtureDir string // Replay recorded responses from this directory
+	Record     bool   // Fetch normally and record responses into FixtureDir
+
+	MatchThreshold    float64 // Minimum fuzzy station match confidence; 0 uses the matcher's default
+	MatchRadiusMeters float64 // How near a source location a station must be to count; 0 uses the matcher's default
+
+	DryRun bool // Don't write reports or fixtures outside the database
+}
+
+// IngestOpts controls a bulk ridership file load
+type IngestOpts struct {
+	Workers int  // Goroutines parsing and matching rows; 0 uses one per CPU
+	DryRun  bool // Don't write the unmatched report
+}
+
+// GTFSOpts controls how a GTFS feed is reconciled with stored stations
+type GTFSOpts struct {
+	RelocateMeters    float64 // Report stations that moved further than this
+	DeactivateRemoved bool    // Mark stations missing from the feed inactive
+}
+
+// Location is where a ridership source places a station, in decimal degrees
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Candidate is a station a matcher considered for a source name
+type Candidate struct {
+	StationID      string
+	StationName    string
+	Score          float64 // Match confidence from 0 to 1
+	DistanceMeters float64 // From the source location, or -1 when it has none
+}
+
+// StationMatcher resolves a ridership source's station identifiers to Station IDs.
+// loc is optional; sources that carry coordinates pass them so that nearby
+// stations score higher and the nearest station can match when names disagree.
+type StationMatcher interface {
+	// MatchStation returns the Station ID for a source station ID, name and location
+	MatchStation(sourceStationID, sourceName string, loc *Location) (string, error)
+	// GetUnmatchedReason explains why a source name could not be matched
+	GetUnmatchedReason(sourceName string, loc *Location) string
+	// Candidates ranks up to n stations by how well they match a source name and
+	// location, best first
+	Candidates(sourceName string, loc *Location, n int) []Candidate
+	// Confirm records a reviewed mapping so later syncs match the source station
+	Confirm(sourceStationID, sourceName, stationID string) error
+}
+
+// CityAdapter implements the city-specific parts of the ETL pipeline
+type CityAdapter interface {
+	// Code is the City.code this adapter handles (e.g. "chicago")
+	Code() string
+	// Name is the display name stored on the City row (e.g. "Chicago CTA")
+	Name() string
+
+	IngestGTFS(dbClient *db.Client, source string, opts GTFSOpts) error
+	// IngestRidership loads a ridership file, stopping early when ctx is cancelled
+	IngestRidership(ctx context.Context, dbClient *db.Client, source string, opts IngestOpts) error
+	SyncRidership(dbClient *db.Client, token string, opts SyncOpts) error
+	// ResyncStations re-fetches the retention window (opts.Days) of ridership for
+	// the given source station IDs, e.g. once they have been mapped to stations
+	ResyncStations(dbClient *db.Client, token string, sourceStationIDs []string, opts SyncOpts) error
+	NewStationMatcher(dbClient *db.Client, cityID string) (StationMatcher, error)
+}
+
+var (
+	mu       sync.RWMutex
+	adapters = make(map[string]CityAdapter)
+)
+
+// Register makes a city adapter available by its code.
+// It is intended to be called from a package init function and panics on duplicates.
+func Register(a CityAdapter) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	code := a.Code()
+	if _, exists := adapters[code]; exists {
+		panic(fmt.Sprintf("adapter: city %q registered twice", code))
+	}
+	adapters[code] = a
+}
+
+// Get returns the adapter registered for a city code
+func Get(code string) (CityAdapter, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	a, ok := adapters[code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported city: %s (available: %v)", code, codesLocked())
+	}
+	return a, nil
+}
+
+// Codes returns the registered city codes in sorted order
+func Codes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return codesLocked()
+}
+
+func codesLocked() []string {
+	codes := make([]string, 0, len(adapters))
+	for code := range adapters {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
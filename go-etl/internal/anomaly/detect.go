@@ -0,0 +1,164 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Kinds of anomaly
+const (
+	KindDrop  = "drop"
+	KindSpike = "spike"
+)
+
+// Severity levels, from least to most severe
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// Options tunes the seasonal baseline detector
+type Options struct {
+	LookbackDays  int     // Days before the latest service date to evaluate
+	BaselineWeeks int     // Same-weekday observations to use as the baseline
+	MinBaseline   int     // Minimum baseline observations before a day can be judged
+	Threshold     float64 // Robust z-score at which a day is anomalous
+	MinChange     float64 // Minimum relative change from the baseline (0.2 = 20%)
+}
+
+// DefaultOptions flags days more than 3.5 robust deviations and at least 20%
+// away from the median of the previous 8 same-weekday observations.
+func DefaultOptions() Options {
+	return Options{
+		LookbackDays:  30,
+		BaselineWeeks: 8,
+		MinBaseline:   4,
+		Threshold:     3.5,
+		MinChange:     0.2,
+	}
+}
+
+// Point is one day's ridership for a station
+type Point struct {
+	Date    time.Time
+	Entries int
+}
+
+// Anomaly is a day whose ridership departs from its weekday baseline
+type Anomaly struct {
+	Date     time.Time
+	Entries  int
+	Expected float64 // Baseline median
+	Score    float64 // Robust z-score; negative for drops
+	Kind     string
+	Severity string
+}
+
+// Detect evaluates each point on or after since against a day-of-week aware
+// baseline: the median and median absolute deviation (MAD) of the same
+// weekday over the preceding weeks. points must be sorted by date.
+func Detect(points []Point, since time.Time, opts Options) []Anomaly {
+	var anomalies []Anomaly
+
+	for i, p := range points {
+		if p.Date.Before(since) {
+			continue
+		}
+
+		// Collect earlier values from the same weekday, most recent first
+		var baseline []float64
+		for j := i - 1; j >= 0 && len(baseline) < opts.BaselineWeeks; j-- {
+			if points[j].Date.Weekday() == p.Date.Weekday() {
+				baseline = append(baseline, float64(points[j].Entries))
+			}
+		}
+		if len(baseline) < opts.MinBaseline {
+			continue
+		}
+
+		expected := median(baseline)
+		deviations := make([]float64, len(baseline))
+		for k, v := range baseline {
+			deviations[k] = math.Abs(v - expected)
+		}
+		mad := median(deviations)
+
+		// A perfectly flat baseline has no MAD; fall back to 5% of the median so
+		// small wobbles aren't flagged but real changes still are
+		scale := mad / 0.6745
+		if scale == 0 {
+			scale = math.Max(expected*0.05, 1)
+		}
+
+		score := (float64(p.Entries) - expected) / scale
+		if math.Abs(score) < opts.Threshold {
+			continue
+		}
+		// Very steady stations have a tiny MAD; ignore statistically unusual but small changes
+		if expected > 0 && math.Abs(float64(p.Entries)-expected)/expected < opts.MinChange {
+			continue
+		}
+
+		a := Anomaly{
+			Date:     p.Date,
+			Entries:  p.Entries,
+			Expected: expected,
+			Score:    score,
+			Kind:     KindSpike,
+			Severity: severity(score, opts.Threshold),
+		}
+		if score < 0 {
+			a.Kind = KindDrop
+			// Zero riders at a normally busy station is a closure or a feed gap
+			if p.Entries == 0 && expected >= 100 {
+				a.Severity = SeverityHigh
+			}
+		}
+		anomalies = append(anomalies, a)
+	}
+
+	return anomalies
+}
+
+// severity grades a robust z-score relative to the detection threshold
+func severity(score, threshold float64) string {
+	abs := math.Abs(score)
+	switch {
+	case abs >= threshold*3:
+		return SeverityHigh
+	case abs >= threshold*1.5:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
+
+// SeverityRank orders severities so reports can filter by a minimum
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	}
+	return 0
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,129 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+)
+
+// firstMonday starts every test series
+var firstMonday = time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// series builds daily points for weeks weeks from firstMonday: Mondays from
+// monday(week), other weekdays 1000 and weekends 300, followed by last on the
+// next Monday
+func series(weeks int, monday func(week int) int, last int) []Point {
+	var points []Point
+	for d := 0; d < weeks*7; d++ {
+		date := firstMonday.AddDate(0, 0, d)
+		entries := 1000
+		switch date.Weekday() {
+		case time.Monday:
+			entries = monday(d / 7)
+		case time.Saturday, time.Sunday:
+			entries = 300
+		}
+		points = append(points, Point{Date: date, Entries: entries})
+	}
+	return append(points, Point{Date: firstMonday.AddDate(0, 0, weeks*7), Entries: last})
+}
+
+func flat(entries int) func(int) int {
+	return func(int) int { return entries }
+}
+
+// alternating returns a and b on alternate weeks, so the baseline median is
+// their mean and its MAD half their difference
+func alternating(a, b int) func(int) int {
+	return func(week int) int {
+		if week%2 == 0 {
+			return a
+		}
+		return b
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		weeks    int
+		monday   func(week int) int
+		last     int
+		kind     string // Empty when no anomaly is expected
+		severity string
+		expected float64
+	}{
+		{"flat baseline, usual day", 8, flat(1000), 1000, "", "", 0},
+		// A flat baseline has no MAD, so 5% of the median is used: 4% is a wobble
+		{"flat baseline, small wobble", 8, flat(1000), 1040, "", "", 0},
+		{"flat baseline, spike", 8, flat(1000), 1300, KindSpike, SeverityMedium, 1000},
+		{"flat baseline, big spike", 8, flat(1000), 2000, KindSpike, SeverityHigh, 1000},
+		{"flat baseline, drop", 8, flat(1000), 780, KindDrop, SeverityLow, 1000},
+		{"flat baseline, zero riders", 8, flat(1000), 0, KindDrop, SeverityHigh, 1000},
+		// z is about -6.7, medium by score, but zero riders at a busy station is high
+		{"noisy baseline, zero riders", 8, alternating(900, 1100), 0, KindDrop, SeverityHigh, 1000},
+		// The same drop at a quiet station keeps its score's severity
+		{"quiet station, zero riders", 8, alternating(45, 55), 0, KindDrop, SeverityMedium, 50},
+		// Statistically unusual for a very steady station, but under MinChange
+		{"steady baseline, small change", 8, alternating(999, 1001), 1100, "", "", 0},
+		{"noisy baseline, within spread", 8, alternating(600, 1400), 1500, "", "", 0},
+		{"too little baseline", 3, flat(1000), 0, "", "", 0},
+		// Only the most recent BaselineWeeks same-weekday values count
+		{"old baseline ignored", 12, func(week int) int {
+			if week < 4 {
+				return 5000
+			}
+			return 1000
+		}, 1000, "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := series(tt.weeks, tt.monday, tt.last)
+			target := points[len(points)-1].Date
+
+			anomalies := Detect(points, target, DefaultOptions())
+			if tt.kind == "" {
+				if len(anomalies) != 0 {
+					t.Errorf("got %+v, want no anomalies", anomalies)
+				}
+				return
+			}
+			if len(anomalies) != 1 {
+				t.Fatalf("got %+v, want one anomaly", anomalies)
+			}
+			a := anomalies[0]
+			if !a.Date.Equal(target) || a.Kind != tt.kind || a.Severity != tt.severity || a.Expected != tt.expected {
+				t.Errorf("got %s %s %s expected %.0f, want %s %s %s expected %.0f",
+					a.Date.Format("2006-01-02"), a.Kind, a.Severity, a.Expected,
+					target.Format("2006-01-02"), tt.kind, tt.severity, tt.expected)
+			}
+			if (a.Kind == KindDrop) != (a.Score < 0) {
+				t.Errorf("%s has score %.2f", a.Kind, a.Score)
+			}
+		})
+	}
+}
+
+func TestDetectSince(t *testing.T) {
+	// Two dropped Mondays; only the one on or after since is reported
+	points := series(8, flat(1000), 0)
+	points[7*7].Entries = 0
+	since := points[len(points)-1].Date
+
+	anomalies := Detect(points, since, DefaultOptions())
+	if len(anomalies) != 1 || !anomalies[0].Date.Equal(since) {
+		t.Errorf("got %+v, want only %s", anomalies, since.Format("2006-01-02"))
+	}
+
+	if all := Detect(points, firstMonday, DefaultOptions()); len(all) != 2 {
+		t.Errorf("without since: got %d anomalies, want 2", len(all))
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if !(SeverityRank(SeverityHigh) > SeverityRank(SeverityMedium) &&
+		SeverityRank(SeverityMedium) > SeverityRank(SeverityLow) &&
+		SeverityRank(SeverityLow) > SeverityRank("unknown")) {
+		t.Error("severities are not ranked high > medium > low > unknown")
+	}
+}
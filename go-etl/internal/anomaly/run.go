@@ -0,0 +1,71 @@
+package anomaly
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// Run detects anomalies over the last opts.LookbackDays of a city's ridership
+// and records them, replacing earlier results for the same station and day.
+// It returns the number of anomalies recorded.
+func Run(dbClient *db.Client, cityCode string, opts Options) (int, error) {
+	maxDate, err := dbClient.GetMaxServiceDate(cityCode)
+	if err != nil {
+		return 0, fmt.Errorf("could not get max service date: %w", err)
+	}
+	if maxDate.IsZero() {
+		return 0, nil // No ridership yet
+	}
+
+	since := truncateDay(maxDate).AddDate(0, 0, -opts.LookbackDays)
+	// Enough history before the window to build every day's baseline
+	from := since.AddDate(0, 0, -7*(opts.BaselineWeeks+1))
+
+	series, err := dbClient.GetDailyRidershipSeries(cityCode, from.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load ridership series: %w", err)
+	}
+
+	recorded := 0
+	for stationID, entries := range series {
+		points := make([]Point, 0, len(entries))
+		for _, e := range entries {
+			date, err := time.Parse("2006-01-02", e.ServiceDate)
+			if err != nil {
+				continue
+			}
+			points = append(points, Point{Date: date, Entries: e.Entries})
+		}
+
+		if err := dbClient.ClearRidershipAnomalies(stationID, since.Format("2006-01-02")); err != nil {
+			return recorded, err
+		}
+
+		for _, a := range Detect(points, since, opts) {
+			err := dbClient.UpsertRidershipAnomaly(db.RidershipAnomaly{
+				StationID:   stationID,
+				ServiceDate: a.Date.Format("2006-01-02"),
+				Kind:        a.Kind,
+				Severity:    a.Severity,
+				Entries:     a.Entries,
+				Expected:    a.Expected,
+				Score:       a.Score,
+			})
+			if err != nil {
+				return recorded, fmt.Errorf("failed to record anomaly: %w", err)
+			}
+			recorded++
+		}
+	}
+
+	log.Printf("Anomaly detection: %d anomalies across %d stations since %s",
+		recorded, len(series), since.Format("2006-01-02"))
+	return recorded, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,43 @@
+package chicago
+
+import (
+	"context"
+
+	"github.com/nate/ghost-stops/go-etl/internal/adapter"
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+const (
+	cityCode = "chicago"
+	cityName = "Chicago CTA"
+)
+
+// Adapter wires the Chicago CTA ingesters into the city registry
+type Adapter struct{}
+
+func init() {
+	adapter.Register(Adapter{})
+}
+
+func (Adapter) Code() string { return cityCode }
+func (Adapter) Name() string { return cityName }
+
+func (Adapter) IngestGTFS(dbClient *db.Client, source string, opts adapter.GTFSOpts) error {
+	return IngestGTFS(dbClient, source, opts)
+}
+
+func (Adapter) IngestRidership(ctx context.Context, dbClient *db.Client, source string, opts adapter.IngestOpts) error {
+	return IngestRidership(ctx, dbClient, source, opts)
+}
+
+func (Adapter) SyncRidership(dbClient *db.Client, token string, opts adapter.SyncOpts) error {
+	return SyncRidership(dbClient, token, opts)
+}
+
+func (Adapter) ResyncStations(dbClient *db.Client, token string, ctaStationIDs []string, opts adapter.SyncOpts) error {
+	return ResyncStations(dbClient, token, ctaStationIDs, opts)
+}
+
+func (Adapter) NewStationMatcher(dbClient *db.Client, cityID string) (adapter.StationMatcher, error) {
+	return NewStationMatcher(dbClient, cityID)
+}
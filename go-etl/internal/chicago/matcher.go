@@ -3,9 +3,35 @@ package chicago
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/nate/ghost-stops/go-etl/internal/adapter"
 	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/gtfs"
+	"github.com/nate/ghost-stops/go-etl/internal/match"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+const (
+	// DefaultMatchThreshold is the confidence a fuzzy candidate needs to be accepted
+	DefaultMatchThreshold = 0.88
+
+	// minMatchMargin is how far the best fuzzy candidate must lead the runner-up;
+	// closer calls are left for a person to resolve
+	minMatchMargin = 0.05
+
+	// lineHintWeight is the share of a candidate's score given to whether the line
+	// inferred from the Socrata suffix is one the station serves
+	lineHintWeight = 0.15
+
+	// DefaultMatchRadiusMeters is how near a source location a station must be for
+	// proximity to count towards its score
+	DefaultMatchRadiusMeters = 250
+
+	// locationWeight is how far a station at the source location is lifted towards
+	// a perfect score; the lift shrinks linearly to nothing at the match radius
+	locationWeight = 0.5
 )
 
 // StationMatcher helps match Socrata station names to GTFS stations
@@ -14,12 +40,28 @@ type StationMatcher struct {
 	cityID        string
 	stations      []Station // All stations for the city
 	lineHints     map[string]string // suffix -> line color
+	specialMappings map[string][]string // Socrata name -> possible GTFS names, normalized
+	aliases       db.StationAliases // normalized StationAlias -> station ID(s)
+	normalizer    *normalize.Normalizer
+
+	// Threshold is the minimum fuzzy match confidence (0-1) accepted without an
+	// exact or special-case match
+	Threshold float64
+
+	// RadiusMeters is how near a source location a station must be to count as
+	// close to it, both for scoring and for nearest-station matching
+	RadiusMeters float64
 }
 
 type Station struct {
-	ID    string
-	Name  string
-	Lines []string
+	ID        string
+	Name      string
+	Lines     []string
+	Latitude  float64
+	Longitude float64
+
+	normalizedName string // Name normalized
+	normalizedBase string // Name without its line suffix, normalized
 }
 
 // NewStationMatcher creates a new station matcher
@@ -30,89 +72,149 @@ func NewStationMatcher(dbClient *db.Client, cityID string) (*StationMatcher, err
 		return nil, fmt.Errorf("failed to load stations: %w", err)
 	}
 
-	// Initialize line hints from known Socrata patterns
-	lineHints := map[string]string{
-		// Hyphen patterns
-		"O'Hare":       "Blue",
-		"North Main":   "Red",
-		"Brown":        "Brown",
-		"Cermak":       "Pink",
-		"Lake":         "Green",
-		"Chinatown":    "Red",
-		"Forest Park":  "Blue",
-		"Dan Ryan":     "Red",
-		"Bronzeville-IIT": "Green",
-		"Skokie":       "Yellow",
-		"McCormick Place": "Green",
-		// Cross-street patterns (slashes)
-		"Milwaukee":    "Blue",
-		"State":        "Red",
-		"Franklin":     "Brown",
-		"Dearborn":     "Blue",
-		"Wells":        "Orange",  // or Brown
-		"Wabash":       "Green",   // or Orange/Pink/Purple
-		"Division":     "Blue",
-		"Clybourn":     "Red",
-		"Halsted":      "Blue",    // or Green or Orange
-		"Archer":       "Orange",
-		// Special cases
-		"Howard":       "Red",
-		"Kimball":      "Brown",
-		"Ashland/63":   "Green",
-		"Cottage Grove": "Green",
-		"Harlem":       "Blue",    // context-dependent
-		"Cumberland":   "Blue",
-		"Jackson":      "Blue",
-		"Monroe":       "Blue",
-		"Adams":        "Pink",
-		"Madison":      "Blue",
-		"Grand":        "Blue",
-		// Purple Line Express
-		"Express":      "Purple",
-		// Additional patterns
-		"O'Hare Airport": "Blue",
-		"Midway Airport": "Orange",
-		"Medical Center": "Blue",
-		"Conservatory":  "Green",
-		"Library":      "Brown",
+	aliases, err := dbClient.GetAllStationAliases(cityID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Line hints, special mappings and normalization rules come from cities/chicago
+	names, err := loadNaming()
+	if err != nil {
+		return nil, err
+	}
+	for i := range stations {
+		stations[i].normalizedName = names.Normalizer.Normalize(stations[i].Name)
+		stations[i].normalizedBase = names.Normalizer.Normalize(extractBaseName(stations[i].Name))
+	}
+	specialMappings := make(map[string][]string, len(names.SpecialMappings))
+	for source, possibleNames := range names.SpecialMappings {
+		for _, name := range possibleNames {
+			specialMappings[source] = append(specialMappings[source], names.Normalizer.Normalize(name))
+		}
 	}
 
 	return &StationMatcher{
-		dbClient:  dbClient,
-		cityID:    cityID,
-		stations:  stations,
-		lineHints: lineHints,
+		dbClient:        dbClient,
+		cityID:          cityID,
+		stations:        stations,
+		lineHints:       names.LineHints,
+		specialMappings: specialMappings,
+		aliases:         aliases,
+		normalizer:      names.Normalizer,
+		Threshold:       DefaultMatchThreshold,
+		RadiusMeters:    DefaultMatchRadiusMeters,
 	}, nil
 }
 
-// MatchStation attempts to match a Socrata station to a GTFS station
-func (m *StationMatcher) MatchStation(ctaStationId string, socrataName string) (stationID string, err error) {
+// applyMatchOpts overrides the matcher's defaults with any set in opts
+func (m *StationMatcher) applyMatchOpts(opts SyncOpts) {
+	if opts.MatchThreshold > 0 {
+		m.Threshold = opts.MatchThreshold
+	}
+	if opts.MatchRadiusMeters > 0 {
+		m.RadiusMeters = opts.MatchRadiusMeters
+	}
+}
+
+// Normalize normalizes a station name with Chicago's rules, as stored aliases are
+func (m *StationMatcher) Normalize(name string) string {
+	return m.normalizer.Normalize(name)
+}
+
+// MatchStation attempts to match a Socrata station to a GTFS station. loc is the
+// station's location when the source gives one, and may be nil.
+func (m *StationMatcher) MatchStation(ctaStationId string, socrataName string, loc *adapter.Location) (stationID string, err error) {
 	// First try direct CTA station ID match
 	id, err := m.dbClient.GetStationIDByCtaStationId(m.cityID, ctaStationId)
 	if err == nil {
 		return id, nil
 	}
 
+	// Then stored aliases, such as those confirmed by review-unmatched, so a
+	// reviewed mapping overrides a wrong name match
+	normalized := m.Normalize(socrataName)
+	if id, ok := m.aliases.Unique[normalized]; ok {
+		return id, nil
+	}
+
+	// Then stations whose names match. Stations that match equally well, such as
+	// same-named stations on lines the name doesn't mention, are told apart by
+	// location when there is one; otherwise the name is left for a person.
+	if tied := m.bestNameMatches(socrataName); len(tied) > 0 {
+		bestMatch, settled := tied[0], true
+		if len(tied) > 1 {
+			bestMatch, settled = nearestStation(loc, tied)
+		}
+		if !settled {
+			return "", fmt.Errorf("%s matches %d stations equally well", socrataName, len(tied))
+		}
+
+		// Update the station with the CTA station ID for future lookups
+		if err := m.dbClient.UpdateStationCtaStationId(bestMatch.ID, ctaStationId); err != nil {
+			// Log but don't fail the match
+			fmt.Printf("Warning: Failed to update CTA station ID: %v\n", err)
+		}
+		return bestMatch.ID, nil
+	}
+
+	// An alias of several stations is only settled by location; otherwise it is
+	// left for a person rather than guessed by the fuzzy fallback
+	if ids, ok := m.aliases.Ambiguous[normalized]; ok {
+		for _, nearby := range m.nearbyStations(loc) {
+			if containsID(ids, nearby.StationID) {
+				fmt.Printf("Matched ambiguous alias %q to %q by location (%.0fm away)\n",
+					socrataName, nearby.StationName, nearby.DistanceMeters)
+				return nearby.StationID, nil
+			}
+		}
+		return "", fmt.Errorf("%s is an alias of %d stations", socrataName, len(ids))
+	}
+
+	// Fall back to the best fuzzy candidate when it is confident and unambiguous.
+	// Fuzzy matches aren't saved as the CTA station ID so that a later alias or
+	// station change can still correct them.
+	if candidate, ok := m.confidentCandidate(socrataName, loc); ok {
+		fmt.Printf("Fuzzy matched %q to %q (confidence %.2f)\n",
+			socrataName, candidate.StationName, candidate.Score)
+		return candidate.StationID, nil
+	}
+
+	// Finally, when the names disagree, to the only station near the source location
+	if nearby := m.nearbyStations(loc); len(nearby) == 1 {
+		fmt.Printf("Matched %q to %q by location (%.0fm away)\n",
+			socrataName, nearby[0].StationName, nearby[0].DistanceMeters)
+		return nearby[0].StationID, nil
+	}
+
+	return "", fmt.Errorf("no match found for %s (%s)", socrataName, normalized)
+}
+
+// bestNameMatches returns the stations whose base name or special mapping best
+// matches a Socrata name, more than one when several match equally well
+func (m *StationMatcher) bestNameMatches(socrataName string) []*Station {
 	// Parse the Socrata name to extract base name and suffix
 	baseName, suffix := parseSocrataName(socrataName)
-	normalizedBase := db.NormalizeStationName(baseName)
+	normalizedBase := m.Normalize(baseName)
 
 	// Infer the line from the suffix
 	inferredLine := m.inferLine(suffix)
 
-	// Try to find a matching station
-	var bestMatch *Station
 	var bestScore int
+	var tied []*Station // Every station scoring bestScore
+	consider := func(station *Station, score int) {
+		switch {
+		case score > bestScore:
+			bestScore, tied = score, []*Station{station}
+		case score == bestScore && score > 0 && tied[len(tied)-1] != station:
+			tied = append(tied, station)
+		}
+	}
 
 	for i := range m.stations {
 		station := &m.stations[i]
 
-		// Normalize the GTFS station name (remove line suffix)
-		gtfsBase := extractBaseName(station.Name)
-		normalizedGtfs := db.NormalizeStationName(gtfsBase)
-
-		// Check if base names match
-		if normalizedBase == normalizedGtfs {
+		// Check if base names (without line suffixes) match
+		if normalizedBase == station.normalizedBase {
 			// Score the match based on line compatibility
 			score := 0
 			if inferredLine != "" && containsLine(station.Lines, inferredLine) {
@@ -123,32 +225,161 @@ func (m *StationMatcher) MatchStation(ctaStationId string, socrataName string) (
 				score = 1 // No line to match against
 			}
 
-			if score > bestScore {
-				bestScore = score
-				bestMatch = station
-			}
+			consider(station, score)
 		}
 
 		// Also check for special mappings
-		if matchesSpecialCase(socrataName, station.Name) {
-			score := 4 // Special case match
-			if score > bestScore {
-				bestScore = score
-				bestMatch = station
-			}
+		if m.matchesSpecialCase(socrataName, station) {
+			consider(station, 4) // Special case match
 		}
 	}
+	return tied
+}
 
-	if bestMatch != nil {
-		// Update the station with the CTA station ID for future lookups
-		if err := m.dbClient.UpdateStationCtaStationId(bestMatch.ID, ctaStationId); err != nil {
-			// Log but don't fail the match
-			fmt.Printf("Warning: Failed to update CTA station ID: %v\n", err)
+// Confirm records a reviewed mapping: the Socrata name becomes an alias of the
+// station, and the CTA station ID is saved unless the station already has one
+func (m *StationMatcher) Confirm(ctaStationId, socrataName, stationID string) error {
+	normalized := m.Normalize(socrataName)
+	if err := m.dbClient.CreateStationAlias(stationID, socrataName, normalized); err != nil {
+		return fmt.Errorf("failed to create alias: %w", err)
+	}
+	m.aliases.Add(normalized, stationID)
+
+	if ctaStationId == "" {
+		return nil
+	}
+	existing, err := m.dbClient.GetStationCtaStationId(stationID)
+	if err != nil {
+		return err
+	}
+	if existing != "" && existing != ctaStationId {
+		fmt.Printf("Note: station already has CTA station ID %s; %s will match by name\n", existing, ctaStationId)
+		return nil
+	}
+	return m.dbClient.UpdateStationCtaStationId(stationID, ctaStationId)
+}
+
+// Candidates ranks up to n stations by how well they match a Socrata name and
+// optional location, best first
+func (m *StationMatcher) Candidates(socrataName string, loc *adapter.Location, n int) []adapter.Candidate {
+	baseName, suffix := parseSocrataName(socrataName)
+	normalizedFull := m.Normalize(socrataName)
+	normalizedBase := m.Normalize(baseName)
+	inferredLine := m.inferLine(suffix)
+
+	candidates := make([]adapter.Candidate, 0, len(m.stations))
+	for i := range m.stations {
+		station := &m.stations[i]
+		score := scoreCandidate(normalizedFull, normalizedBase, inferredLine, station)
+		distance := distanceTo(loc, station)
+		if distance >= 0 && distance < m.RadiusMeters {
+			proximity := 1 - distance/m.RadiusMeters
+			score += locationWeight * proximity * (1 - score)
 		}
-		return bestMatch.ID, nil
+		candidates = append(candidates, adapter.Candidate{
+			StationID:      station.ID,
+			StationName:    station.Name,
+			Score:          score,
+			DistanceMeters: distance,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// confidentCandidate returns the best fuzzy candidate if it meets the threshold and
+// leads the runner-up by at least minMatchMargin
+func (m *StationMatcher) confidentCandidate(socrataName string, loc *adapter.Location) (adapter.Candidate, bool) {
+	candidates := m.Candidates(socrataName, loc, 2)
+	if len(candidates) == 0 || candidates[0].Score < m.Threshold {
+		return adapter.Candidate{}, false
 	}
+	if len(candidates) > 1 && candidates[0].Score-candidates[1].Score < minMatchMargin {
+		return adapter.Candidate{}, false
+	}
+	return candidates[0], true
+}
 
-	return "", fmt.Errorf("no match found for %s (%s)", socrataName, normalizedBase)
+// nearbyStations lists the stations within the match radius of loc, nearest first
+func (m *StationMatcher) nearbyStations(loc *adapter.Location) []adapter.Candidate {
+	var nearby []adapter.Candidate
+	for i := range m.stations {
+		station := &m.stations[i]
+		if distance := distanceTo(loc, station); distance >= 0 && distance < m.RadiusMeters {
+			nearby = append(nearby, adapter.Candidate{
+				StationID:      station.ID,
+				StationName:    station.Name,
+				DistanceMeters: distance,
+			})
+		}
+	}
+	sort.Slice(nearby, func(i, j int) bool {
+		return nearby[i].DistanceMeters < nearby[j].DistanceMeters
+	})
+	return nearby
+}
+
+// nearestStation returns the station closest to loc. settled is false when loc or
+// a station's coordinates are unknown, or the two closest are equally far; the
+// first station is returned then.
+func nearestStation(loc *adapter.Location, stations []*Station) (nearest *Station, settled bool) {
+	best, second := -1.0, -1.0
+	for _, station := range stations {
+		d := distanceTo(loc, station)
+		if d < 0 {
+			return stations[0], false
+		}
+		if best < 0 || d < best {
+			nearest, best, second = station, d, best
+		} else if second < 0 || d < second {
+			second = d
+		}
+	}
+	return nearest, second < 0 || best < second
+}
+
+// distanceTo returns the meters between loc and a station, or -1 when loc is nil
+// or the station has no coordinates
+func distanceTo(loc *adapter.Location, station *Station) float64 {
+	if loc == nil || (station.Latitude == 0 && station.Longitude == 0) {
+		return -1
+	}
+	return gtfs.DistanceMeters(loc.Latitude, loc.Longitude, station.Latitude, station.Longitude)
+}
+
+// scoreCandidate rates a station against a Socrata name from 0 to 1. Name
+// similarity is taken from the better of the full names and the names without
+// line suffixes; the rest of the score is line agreement, neutral when either
+// side has no line information.
+func scoreCandidate(normalizedFull, normalizedBase, inferredLine string, station *Station) float64 {
+	name := max(nameSimilarity(normalizedFull, station.normalizedName),
+		nameSimilarity(normalizedBase, station.normalizedBase))
+
+	line := 0.5
+	if inferredLine != "" && len(station.Lines) > 0 {
+		if containsLine(station.Lines, inferredLine) {
+			line = 1
+		} else {
+			line = 0
+		}
+	}
+
+	return (1-lineHintWeight)*name + lineHintWeight*line
+}
+
+// nameSimilarity blends word-level and character-level similarity of two normalized
+// names, so reordered words and small spelling differences both score well
+func nameSimilarity(a, b string) float64 {
+	sortedA, sortedB := match.SortTokens(a), match.SortTokens(b)
+	return 0.5*match.TokenSetSimilarity(a, b) +
+		0.25*match.JaroWinkler(sortedA, sortedB) +
+		0.25*match.EditSimilarity(sortedA, sortedB)
 }
 
 // inferLine attempts to infer the line color from a station suffix
@@ -198,6 +429,31 @@ func extractBaseName(name string) string {
 	return name
 }
 
+// stationNames returns the names of the given stations, in the same order
+func (m *StationMatcher) stationNames(ids []string) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id
+		for _, station := range m.stations {
+			if station.ID == id {
+				names[i] = station.Name
+				break
+			}
+		}
+	}
+	return names
+}
+
+// containsID reports whether ids includes id
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
 // containsLine checks if a station serves a particular line
 func containsLine(lines []string, targetLine string) bool {
 	for _, line := range lines {
@@ -212,7 +468,7 @@ func containsLine(lines []string, targetLine string) bool {
 func loadStations(dbClient *db.Client, cityID string) ([]Station, error) {
 	// This is a simplified query - you may need to adjust based on your actual schema
 	query := `
-		SELECT id, name, lines
+		SELECT id, name, lines, latitude, longitude
 		FROM Station
 		WHERE cityId = ?
 	`
@@ -228,7 +484,7 @@ func loadStations(dbClient *db.Client, cityID string) ([]Station, error) {
 		var s Station
 		var linesJSON string
 
-		err := rows.Scan(&s.ID, &s.Name, &linesJSON)
+		err := rows.Scan(&s.ID, &s.Name, &linesJSON, &s.Latitude, &s.Longitude)
 		if err != nil {
 			return nil, err
 		}
@@ -247,43 +503,10 @@ func loadStations(dbClient *db.Client, cityID string) ([]Station, error) {
 }
 
 // matchesSpecialCase handles special station name mappings
-func matchesSpecialCase(socrataName, gtfsName string) bool {
-	// Define special mappings
-	specialMappings := map[string][]string{
-		// Socrata name -> possible GTFS names
-		"Library":                    {"Harold Washington Library-State/Van Buren"},
-		"Medical Center":             {"Illinois Medical District"},
-		"O'Hare Airport":             {"O'Hare"},
-		"95th/Dan Ryan":              {"95th", "95th/Ran Ryan", "95th/Dan Ryan"},
-		"35-Bronzeville-IIT":         {"35th-Bronzeville-IIT", "Bronzeville-IIT"},
-		"Midway Airport":             {"Midway"},
-		"UIC-Halsted":                {"UIC-Halsted", "Halsted (Blue)", "Halsted (Green)"},
-		"54th/Cermak":                {"54th/Cermak", "Cermak (Pink)"},
-		"Cermak-McCormick Place":     {"Cermak-McCormick Place", "McCormick Place"},
-		"35th/Archer":                {"35th/Archer", "Archer"},
-		"Kedzie-Homan-Forest Park":   {"Kedzie-Homan", "Kedzie (Blue - Forest Park Branch)"},
-		"East 63rd-Cottage Grove":    {"Cottage Grove", "63rd (Green)"},
-		"Harlem-Forest Park":         {"Harlem (Blue - Forest Park Branch)"},
-		"Harlem-Lake":                {"Harlem (Green)", "Harlem/Lake"},
-		"Harlem-O'Hare":              {"Harlem (Blue - O'Hare Branch)"},
-		"State/Lake":                 {"State/Lake", "Lake (Red)"},
-		"Conservatory":               {"Conservatory-Central Park Drive", "Central Park"},
-		"Oakton-Skokie":              {"Oakton-Skokie", "Skokie"},
-		"Jefferson Park":             {"Jefferson Park"},
-		"North/Clybourn":             {"North/Clybourn"},
-		"Adams/Wabash":               {"Adams/Wabash"},
-		"Clark/Division":             {"Clark/Division"},
-		"Clark/Lake":                 {"Clark/Lake"},
-		"Division/Milwaukee":         {"Division"},
-		"Quincy/Wells":               {"Quincy/Wells", "Quincy"},
-		"Washington/Wells":           {"Washington/Wells"},
-		"Washington/Wabash":          {"Washington/Wabash"},
-		"Washington/Dearborn":        {"Washington"},
-	}
-
-	// Normalize both names for comparison
-	normalizedSocrata := db.NormalizeStationName(socrataName)
-	normalizedGtfs := db.NormalizeStationName(gtfsName)
+func (m *StationMatcher) matchesSpecialCase(socrataName string, station *Station) bool {
+	// Compare normalized names
+	normalizedSocrata := m.Normalize(socrataName)
+	normalizedGtfs := station.normalizedName
 
 	// Check if exact normalized match
 	if normalizedSocrata == normalizedGtfs {
@@ -291,9 +514,9 @@ func matchesSpecialCase(socrataName, gtfsName string) bool {
 	}
 
 	// Check special mappings
-	if possibleNames, ok := specialMappings[socrataName]; ok {
+	if possibleNames, ok := m.specialMappings[socrataName]; ok {
 		for _, possibleName := range possibleNames {
-			if db.NormalizeStationName(possibleName) == normalizedGtfs {
+			if possibleName == normalizedGtfs {
 				return true
 			}
 		}
@@ -303,17 +526,27 @@ func matchesSpecialCase(socrataName, gtfsName string) bool {
 }
 
 // GetUnmatchedReason provides a human-readable reason for why a station couldn't be matched
-func (m *StationMatcher) GetUnmatchedReason(socrataName string) string {
+func (m *StationMatcher) GetUnmatchedReason(socrataName string, loc *adapter.Location) string {
+	if ids, ok := m.aliases.Ambiguous[m.Normalize(socrataName)]; ok {
+		return fmt.Sprintf("Ambiguous alias: refers to %d stations (%s)",
+			len(ids), strings.Join(m.stationNames(ids), ", "))
+	}
+	if tied := m.bestNameMatches(socrataName); len(tied) > 1 {
+		names := make([]string, len(tied))
+		for i, station := range tied {
+			names[i] = fmt.Sprintf("%s %v", station.Name, station.Lines)
+		}
+		return fmt.Sprintf("Name matches %d stations equally well (%s) and location doesn't settle it",
+			len(tied), strings.Join(names, ", "))
+	}
+
 	baseName, suffix := parseSocrataName(socrataName)
-	normalizedBase := db.NormalizeStationName(baseName)
+	normalizedBase := m.Normalize(baseName)
 	inferredLine := m.inferLine(suffix)
 
 	// Check if any station has a similar base name
 	for _, station := range m.stations {
-		gtfsBase := extractBaseName(station.Name)
-		normalizedGtfs := db.NormalizeStationName(gtfsBase)
-
-		if normalizedBase == normalizedGtfs {
+		if normalizedBase == station.normalizedBase {
 			if inferredLine != "" && !containsLine(station.Lines, inferredLine) {
 				return fmt.Sprintf("Base name matches '%s' but line mismatch (expected: %s, found: %v)",
 					station.Name, inferredLine, station.Lines)
@@ -321,8 +554,28 @@ func (m *StationMatcher) GetUnmatchedReason(socrataName string) string {
 		}
 	}
 
-	// Check for close matches
-	// You could add fuzzy matching logic here
+	// Explain why the closest fuzzy candidates weren't accepted
+	reason := "No station found with matching base name"
+	candidates := m.Candidates(socrataName, loc, 2)
+	if len(candidates) > 0 {
+		best := candidates[0]
+		if best.Score < m.Threshold {
+			reason = fmt.Sprintf("Closest match '%s' scores %.2f, below threshold %.2f",
+				best.StationName, best.Score, m.Threshold)
+		} else if len(candidates) > 1 {
+			reason = fmt.Sprintf("Ambiguous: '%s' (%.2f) and '%s' (%.2f) score too close to choose",
+				best.StationName, best.Score, candidates[1].StationName, candidates[1].Score)
+		}
+	}
+
+	// And why the location didn't settle it
+	if loc != nil {
+		if nearby := m.nearbyStations(loc); len(nearby) == 0 {
+			reason += fmt.Sprintf("; no station within %.0fm", m.RadiusMeters)
+		} else {
+			reason += fmt.Sprintf("; %d stations within %.0fm", len(nearby), m.RadiusMeters)
+		}
+	}
 
-	return "No station found with matching base name"
+	return reason
 }
\ No newline at end of file
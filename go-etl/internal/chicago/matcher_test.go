@@ -0,0 +1,99 @@
+package chicago
+
+import (
+	"testing"
+
+	"github.com/nate/ghost-stops/go-etl/internal/adapter"
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// seedWestern stores two stations named Western, on lines a bare "Western"
+// doesn't name, and returns the city ID and the Blue and Brown Line station IDs
+func seedWestern(t *testing.T, dbClient *db.Client) (cityID, blue, brown string) {
+	t.Helper()
+
+	cityID, err := dbClient.GetCityID(cityCode, cityName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbClient.UpsertStation(cityID, "40220", "Western", 41.916157, -87.687364, `["Blue"]`); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbClient.UpsertStation(cityID, "41480", "Western", 41.966163, -87.688502, `["Brown"]`); err != nil {
+		t.Fatal(err)
+	}
+	if blue, err = dbClient.GetStationIDByExternalID(cityID, "40220"); err != nil {
+		t.Fatal(err)
+	}
+	if brown, err = dbClient.GetStationIDByExternalID(cityID, "41480"); err != nil {
+		t.Fatal(err)
+	}
+	inTempDir(t)
+	return cityID, blue, brown
+}
+
+func TestMatchStationBreaksTiesByLocation(t *testing.T) {
+	dbClient := newTestDB(t)
+	cityID, _, brownID := seedWestern(t, dbClient)
+
+	matcher, err := NewStationMatcher(dbClient, cityID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	brown := &adapter.Location{Latitude: 41.9662, Longitude: -87.6885}
+
+	// The nearer station wins and keeps the ID
+	id, err := matcher.MatchStation("41480", "Western", brown)
+	if err != nil {
+		t.Fatalf("MatchStation with location: %v", err)
+	}
+	if id != brownID {
+		t.Errorf("matched %q, want the Brown Line Western %q", id, brownID)
+	}
+	if saved, err := dbClient.GetStationIDByCtaStationId(cityID, "41480"); err != nil || saved != id {
+		t.Errorf("CTA station ID 41480: saved for %q (%v), want %q", saved, err, id)
+	}
+}
+
+func TestMatchStationLeavesUnsettledTies(t *testing.T) {
+	dbClient := newTestDB(t)
+	cityID, _, _ := seedWestern(t, dbClient)
+
+	matcher, err := NewStationMatcher(dbClient, cityID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a location either station could be meant
+	if id, err := matcher.MatchStation("99001", "Western", nil); err == nil {
+		t.Errorf("MatchStation without location: matched %q, want an error", id)
+	}
+	if _, err := dbClient.GetStationIDByCtaStationId(cityID, "99001"); err == nil {
+		t.Error("CTA station ID saved for a tie location didn't settle")
+	}
+	if reason := matcher.GetUnmatchedReason("Western", nil); reason == "" {
+		t.Error("no unmatched reason for a tied name")
+	}
+}
+
+func TestMatchStationPrefersAliases(t *testing.T) {
+	dbClient := newTestDB(t)
+	cityID, _, brownID := seedWestern(t, dbClient)
+
+	// A reviewed mapping settles the name, even though it matches both stations
+	if err := dbClient.CreateStationAlias(brownID, "Western", "western"); err != nil {
+		t.Fatal(err)
+	}
+	matcher, err := NewStationMatcher(dbClient, cityID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := matcher.MatchStation("99002", "Western", nil)
+	if err != nil {
+		t.Fatalf("MatchStation: %v", err)
+	}
+	if id != brownID {
+		t.Errorf("matched %q, want the aliased Brown Line Western %q", id, brownID)
+	}
+}
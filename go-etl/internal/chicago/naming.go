@@ -0,0 +1,28 @@
+package chicago
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nate/ghost-stops/go-etl/internal/naming"
+)
+
+var (
+	namingOnce sync.Once
+	namingData *naming.Data
+	namingErr  error
+)
+
+// loadNaming reads Chicago's line names, line hints and special mappings from
+// cities/chicago once per run
+func loadNaming() (*naming.Data, error) {
+	namingOnce.Do(func() {
+		namingData, namingErr = naming.Load(cityCode)
+		if namingErr == nil {
+			fmt.Printf("Loaded %s naming data from %s (%d lines, %d line hints, %d special mappings)\n",
+				cityCode, namingData.Source, len(namingData.Lines), len(namingData.LineHints),
+				len(namingData.SpecialMappings))
+		}
+	})
+	return namingData, namingErr
+}
@@ -0,0 +1,78 @@
+package chicago
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/socrata"
+)
+
+// ResyncStations re-fetches the last opts.Days of ridership for the given CTA
+// station IDs and upserts whatever now matches, e.g. after review-unmatched has
+// mapped them. It leaves the sync checkpoint and watermark alone.
+func ResyncStations(dbClient *db.Client, token string, ctaStationIDs []string, opts SyncOpts) error {
+	if len(ctaStationIDs) == 0 {
+		return nil
+	}
+
+	cityID, err := dbClient.GetCityID(cityCode, cityName)
+	if err != nil {
+		return fmt.Errorf("could not get city id for chicago: %w", err)
+	}
+
+	matcher, err := NewStationMatcher(dbClient, cityID)
+	if err != nil {
+		return fmt.Errorf("failed to create station matcher: %w", err)
+	}
+	matcher.applyMatchOpts(opts)
+
+	client, err := newSocrataClient(token, opts)
+	if err != nil {
+		return err
+	}
+
+	days := opts.Days
+	if days <= 0 {
+		days = 365
+	}
+	pageSize := opts.Limit
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	quoted := make([]string, len(ctaStationIDs))
+	for i, id := range ctaStationIDs {
+		quoted[i] = socrata.Quote(id)
+	}
+	query := ridershipQuery(since, "").Where("station_id IN (" + strings.Join(quoted, ", ") + ")")
+
+	log.Printf("Re-fetching ridership since %s for CTA stations %s",
+		since.Format("2006-01-02"), strings.Join(ctaStationIDs, ", "))
+
+	state := newSyncState(matcher)
+	err = socrata.EachPage(client, ridershipDataset, query, pageSize, 0,
+		func(records []SocrataRecord, next int) error {
+			dbRecords := state.match(records)
+			if len(dbRecords) == 0 {
+				return nil
+			}
+			stats, err := dbClient.InsertRidershipDailyBatch(dbRecords)
+			if err != nil {
+				return fmt.Errorf("failed to batch insert ridership data: %w", err)
+			}
+			state.upserts.Add(stats)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("failed to re-fetch socrata data: %w", err)
+	}
+
+	log.Printf("Re-fetched %d rows: %d new, %d changed, %d identical, %d still unmatched",
+		state.totalRecords, state.upserts.Inserted, state.upserts.Updated,
+		state.upserts.Unchanged, state.skippedCount)
+	return nil
+}
@@ -1,35 +1,82 @@
 package chicago
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/nate/ghost-stops/go-etl/internal/adapter"
 	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
 )
 
-// IngestRidership processes CTA ridership data
-func IngestRidership(dbClient *db.Client, source string) error {
+const (
+	// ingestChunkRows is how many CSV rows the reader hands a worker at a time
+	ingestChunkRows = 1000
+
+	// ingestBatchSize is how many ridership rows the writer upserts per transaction
+	ingestBatchSize = 1000
+
+	// ingestProgressInterval is how often a running load reports its progress
+	ingestProgressInterval = 2 * time.Second
+)
+
+// ridershipColumns locates the fields a ridership CSV row is read from
+type ridershipColumns struct {
+	station, date, rides int
+	dayType              int // -1 when the file has no day type column
+}
+
+// csvChunk is a run of consecutive CSV rows; seq orders chunks as they were read
+type csvChunk struct {
+	seq  int
+	rows [][]string
+}
+
+// ingestedChunk is a chunk parsed and matched by a worker
+type ingestedChunk struct {
+	seq       int
+	rows      int
+	records   []db.RidershipRecord
+	unmatched map[string]int // Station name -> rows
+	ambiguous map[string]int // Station name -> rows, for aliases of several stations
+}
+
+// IngestRidership processes CTA ridership data. Rows flow from a single CSV
+// reader through a pool of workers that parse and match them to a single writer
+// that upserts them in batches, in file order. Cancelling ctx stops the load;
+// batches already written stay, and re-running the load is safe.
+func IngestRidership(ctx context.Context, dbClient *db.Client, source string, opts adapter.IngestOpts) error {
 	// Get Chicago city ID
-	cityID, err := dbClient.GetCityID("chicago", "Chicago CTA")
+	cityID, err := dbClient.GetCityID(cityCode, cityName)
 	if err != nil {
 		return fmt.Errorf("failed to get city ID: %w", err)
 	}
 
-	// Open data source
+	// Open data source; size is -1 when unknown
 	var reader io.Reader
+	var size int64 = -1
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
-		resp, err := http.Get(source)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return fmt.Errorf("failed to download ridership data: %w", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
 			return fmt.Errorf("failed to download ridership data: %w", err)
 		}
 		defer resp.Body.Close()
 		reader = resp.Body
+		size = resp.ContentLength
 	} else {
 		file, err := os.Open(source)
 		if err != nil {
@@ -37,10 +84,14 @@ func IngestRidership(dbClient *db.Client, source string) error {
 		}
 		defer file.Close()
 		reader = file
+		if info, err := file.Stat(); err == nil {
+			size = info.Size()
+		}
 	}
+	counted := &countingReader{r: reader}
 
 	// Parse CSV
-	csvReader := csv.NewReader(reader)
+	csvReader := csv.NewReader(counted)
 	header, err := csvReader.Read()
 	if err != nil {
 		return fmt.Errorf("failed to read header: %w", err)
@@ -87,35 +138,251 @@ func IngestRidership(dbClient *db.Client, source string) error {
 		return fmt.Errorf("could not find required columns. Found: %v", header)
 	}
 
+	cols := ridershipColumns{
+		station: colIndex[stationNameCol],
+		date:    colIndex[serviceDateCol],
+		rides:   colIndex[ridesCol],
+		dayType: -1,
+	}
+	// Day type is optional; compute derives it from the date when absent
+	if i, ok := colIndex["daytype"]; ok {
+		cols.dayType = i
+	}
+
+	// Aliases are stored normalized with Chicago's rules
+	names, err := loadNaming()
+	if err != nil {
+		return err
+	}
+
 	// Pre-load all station aliases for faster matching
 	aliases, err := dbClient.GetAllStationAliases(cityID)
 	if err != nil {
 		return fmt.Errorf("failed to get station aliases: %w", err)
 	}
-	fmt.Printf("Loaded %d station aliases\n", len(aliases))
+	fmt.Printf("Loaded %d station aliases (%d ambiguous)\n",
+		len(aliases.Unique)+len(aliases.Ambiguous), len(aliases.Ambiguous))
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Bounded channels, plus a cap on chunks between the reader and the writer, keep
+	// memory flat however far the reader gets ahead
+	chunks := make(chan csvChunk, workers)
+	results := make(chan ingestedChunk, workers)
+	inFlight := make(chan struct{}, 4*workers)
+
+	var readErr error
+	go func() {
+		defer close(chunks)
+		readErr = readChunks(ctx, csvReader, chunks, inFlight)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				select {
+				case results <- ingestChunk(chunk, cols, names.Normalizer, aliases):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
 	// Track unmatched stations
 	unmatchedStations := make(map[string]int) // station name -> count
-	
-	// Batch records for insertion
+	ambiguousRows := make(map[string]int)
+
+	// Write batches in file order, so a row repeated in the file ends as it would
+	// have sequentially
 	var batch []db.RidershipRecord
-	batchSize := 1000
+	var upserts db.RidershipUpsertStats
+	pending := make(map[int]ingestedChunk)
+	next := 0
 	totalCount := 0
+	progress := newIngestProgress(counted, size)
+	ticker := time.NewTicker(ingestProgressInterval)
+	defer ticker.Stop()
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		stats, err := dbClient.InsertRidershipDailyBatch(batch)
+		if err != nil {
+			return fmt.Errorf("failed to insert batch: %w", err)
+		}
+		upserts.Add(stats)
+		batch = nil // Clear the batch
+		return nil
+	}
+
+write:
+	for {
+		select {
+		case <-ctx.Done():
+			break write
+		case <-ticker.C:
+			progress.report(totalCount)
+		case chunk, ok := <-results:
+			if !ok {
+				break write
+			}
+			pending[chunk.seq] = chunk
+			for {
+				chunk, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				<-inFlight
+
+				totalCount += chunk.rows
+				for name, n := range chunk.unmatched {
+					unmatchedStations[name] += n
+				}
+				for name, n := range chunk.ambiguous {
+					ambiguousRows[name] += n
+				}
+				for _, record := range chunk.records {
+					batch = append(batch, record)
+					// If batch is full, insert it
+					if len(batch) >= ingestBatchSize {
+						if err := flush(); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("ridership load stopped after %d rows (%d new, %d changed rows written): %w",
+			totalCount, upserts.Inserted, upserts.Updated, err)
+	}
+	if readErr != nil {
+		return fmt.Errorf("error reading record: %w", readErr)
+	}
+
+	// Insert any remaining records
+	if err := flush(); err != nil {
+		return fmt.Errorf("failed to insert final batch: %w", err)
+	}
+
+	// Names of the stations behind ambiguous aliases, for the report
+	ambiguousStations := make(map[string]ambiguousName, len(ambiguousRows))
+	if len(ambiguousRows) > 0 {
+		stations, err := dbClient.GetStations(cityID)
+		if err != nil {
+			return fmt.Errorf("failed to get stations: %w", err)
+		}
+		stationNames := make(map[string]string, len(stations))
+		for _, s := range stations {
+			stationNames[s.ID] = s.Name
+		}
+		for name, n := range ambiguousRows {
+			a := ambiguousName{Occurrences: n}
+			for _, id := range aliases.Ambiguous[names.Normalizer.Normalize(name)] {
+				a.Stations = append(a.Stations, stationNames[id])
+			}
+			ambiguousStations[name] = a
+		}
+	}
+
+	// Write unmatched stations report
+	if (len(unmatchedStations) > 0 || len(ambiguousStations) > 0) && opts.DryRun {
+		fmt.Println("Dry run: not writing docs/chicago-unmatched-stations.md")
+	} else if len(unmatchedStations) > 0 || len(ambiguousStations) > 0 {
+		err = writeUnmatchedReport(unmatchedStations, ambiguousStations)
+		if err != nil {
+			fmt.Printf("Warning: Failed to write unmatched stations report: %v\n", err)
+		}
+	}
+
+	elapsed := time.Since(progress.start)
+	fmt.Printf("Processed %d ridership records in %s (%.0f rows/s, %d workers)\n",
+		totalCount, elapsed.Round(time.Millisecond), float64(totalCount)/max(elapsed.Seconds(), 0.001), workers)
+	fmt.Printf("New rows: %d, changed: %d, identical: %d\n", upserts.Inserted, upserts.Updated, upserts.Unchanged)
+	fmt.Printf("Found %d unique unmatched station names\n", len(unmatchedStations))
+	if len(ambiguousStations) > 0 {
+		fmt.Printf("⚠️  Skipped %d names that are aliases of several stations (see go-etl aliases check)\n",
+			len(ambiguousStations))
+	}
+
+	return nil
+}
+
+// readChunks reads CSV rows into chunks of ingestChunkRows, taking an inFlight
+// slot for each chunk, until the input ends or ctx is cancelled
+func readChunks(ctx context.Context, r *csv.Reader, chunks chan<- csvChunk, inFlight chan<- struct{}) error {
+	seq := 0
+	send := func(rows [][]string) bool {
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+		select {
+		case chunks <- csvChunk{seq: seq, rows: rows}:
+			seq++
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
 
-	// Process records
+	rows := make([][]string, 0, ingestChunkRows)
 	for {
-		record, err := csvReader.Read()
+		record, err := r.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("error reading record: %w", err)
+			return err
 		}
+		rows = append(rows, record)
+		if len(rows) == ingestChunkRows {
+			if !send(rows) {
+				return nil
+			}
+			rows = make([][]string, 0, ingestChunkRows)
+		}
+	}
+	if len(rows) > 0 {
+		send(rows)
+	}
+	return nil
+}
 
-		totalCount++
-		stationName := record[colIndex[stationNameCol]]
-		serviceDateStr := record[colIndex[serviceDateCol]]
-		ridesStr := record[colIndex[ridesCol]]
+// ingestChunk parses and matches a chunk of CSV rows
+func ingestChunk(chunk csvChunk, cols ridershipColumns, n *normalize.Normalizer, aliases db.StationAliases) ingestedChunk {
+	result := ingestedChunk{
+		seq:       chunk.seq,
+		rows:      len(chunk.rows),
+		records:   make([]db.RidershipRecord, 0, len(chunk.rows)),
+		unmatched: make(map[string]int),
+		ambiguous: make(map[string]int),
+	}
+
+	for _, record := range chunk.rows {
+		stationName := record[cols.station]
+		serviceDateStr := record[cols.date]
+		ridesStr := record[cols.rides]
 
 		// Parse date (handle both MM/DD/YYYY and YYYY-MM-DD formats)
 		var serviceDate time.Time
@@ -141,56 +408,80 @@ func IngestRidership(dbClient *db.Client, source string) error {
 		}
 
 		// Normalize station name for matching
-		normalized := db.NormalizeStationName(stationName)
+		normalized := n.Normalize(stationName)
 
-		// Try to find station in our alias cache
-		stationID, ok := aliases[normalized]
+		// Try to find station in our alias cache; names that are aliases of several
+		// stations aren't attributed to any of them
+		stationID, ok := aliases.Unique[normalized]
 		if !ok {
-			unmatchedStations[stationName]++
+			if _, ambiguous := aliases.Ambiguous[normalized]; ambiguous {
+				result.ambiguous[stationName]++
+			} else {
+				result.unmatched[stationName]++
+			}
 			continue
 		}
 
-		// Add to batch
-		batch = append(batch, db.RidershipRecord{
+		dayType := ""
+		if cols.dayType >= 0 {
+			dayType = parseDayType(record[cols.dayType])
+		}
+
+		result.records = append(result.records, db.RidershipRecord{
 			StationID:   stationID,
 			ServiceDate: serviceDate.Format("2006-01-02 15:04:05"),
 			Entries:     rides,
+			DayType:     dayType,
 		})
-
-		// If batch is full, insert it
-		if len(batch) >= batchSize {
-			err = dbClient.InsertRidershipDailyBatch(batch)
-			if err != nil {
-				return fmt.Errorf("failed to insert batch: %w", err)
-			}
-			batch = nil // Clear the batch
-		}
 	}
 
-	// Insert any remaining records
-	if len(batch) > 0 {
-		err = dbClient.InsertRidershipDailyBatch(batch)
-		if err != nil {
-			return fmt.Errorf("failed to insert final batch: %w", err)
-		}
-	}
+	return result
+}
 
-	// Write unmatched stations report
-	if len(unmatchedStations) > 0 {
-		err = writeUnmatchedReport(unmatchedStations)
-		if err != nil {
-			fmt.Printf("Warning: Failed to write unmatched stations report: %v\n", err)
-		}
-	}
+// countingReader counts the bytes read through it, for progress reporting
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
 
-	fmt.Printf("Processed %d ridership records\n", totalCount)
-	fmt.Printf("Found %d unique unmatched station names\n", len(unmatchedStations))
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
 
-	return nil
+// ingestProgress prints rows/sec and, when the input size is known, an ETA
+type ingestProgress struct {
+	start time.Time
+	read  *countingReader
+	size  int64
+}
+
+func newIngestProgress(read *countingReader, size int64) *ingestProgress {
+	return &ingestProgress{start: time.Now(), read: read, size: size}
 }
 
-// writeUnmatchedReport creates a markdown file with unmatched stations
-func writeUnmatchedReport(unmatched map[string]int) error {
+func (p *ingestProgress) report(rows int) {
+	elapsed := time.Since(p.start)
+	rate := float64(rows) / max(elapsed.Seconds(), 0.001)
+	line := fmt.Sprintf("  %d rows (%.0f rows/s)", rows, rate)
+
+	if read := p.read.n.Load(); p.size > 0 && read > 0 {
+		done := min(float64(read)/float64(p.size), 1)
+		eta := time.Duration(float64(elapsed) * (1 - done) / done)
+		line += fmt.Sprintf(", %.1f%%, ETA %s", 100*done, eta.Round(time.Second))
+	}
+	fmt.Println(line)
+}
+
+// ambiguousName is a ridership name whose normalized form is an alias of several stations
+type ambiguousName struct {
+	Occurrences int
+	Stations    []string
+}
+
+// writeUnmatchedReport creates a markdown file with unmatched and ambiguous stations
+func writeUnmatchedReport(unmatched map[string]int, ambiguous map[string]ambiguousName) error {
 	// Create docs directory if it doesn't exist
 	err := os.MkdirAll("docs", 0755)
 	if err != nil {
@@ -214,12 +505,28 @@ func writeUnmatchedReport(unmatched map[string]int) error {
 		fmt.Fprintf(file, "| %s | %d |\n", station, count)
 	}
 
+	if len(ambiguous) > 0 {
+		fmt.Fprintln(file)
+		fmt.Fprintln(file, "## Ambiguous Names")
+		fmt.Fprintln(file)
+		fmt.Fprintln(file, "These names are aliases of more than one station, so their rides were not attributed to any:")
+		fmt.Fprintln(file)
+		fmt.Fprintln(file, "| Station Name | Occurrences | Stations |")
+		fmt.Fprintln(file, "|--------------|-------------|----------|")
+		for name, a := range ambiguous {
+			fmt.Fprintf(file, "| %s | %d | %s |\n", name, a.Occurrences, strings.Join(a.Stations, ", "))
+		}
+	}
+
 	fmt.Fprintln(file)
 	fmt.Fprintln(file, "## Resolution Steps")
 	fmt.Fprintln(file)
 	fmt.Fprintln(file, "1. Check if these are old/renamed stations")
-	fmt.Fprintln(file, "2. Add entries to the StationAlias table for known mappings")
+	fmt.Fprintln(file, "2. Map known names with `go-etl review-unmatched --city=chicago --report=docs/chicago-unmatched-stations.md`,")
+	fmt.Fprintln(file, "   which stores them in the StationAlias table, then re-run the ridership load")
 	fmt.Fprintln(file, "3. Some may be bus terminals or non-rail stations")
+	fmt.Fprintln(file, "4. For ambiguous names, `go-etl aliases check --city=chicago` lists the stations sharing")
+	fmt.Fprintln(file, "   each alias; remove any wrong alias from the StationAlias table")
 
 	return nil
 }
\ No newline at end of file
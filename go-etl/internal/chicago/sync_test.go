@@ -0,0 +1,287 @@
+package chicago
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/socrata"
+)
+
+// fixtureDir holds three recorded pages (limit 4) of ridership since 2024-06-01
+// for Clark/Lake, Howard and the closed Madison/Wabash station
+const fixtureDir = "testdata/socrata"
+
+// newTestDB applies the Prisma migrations to an empty SQLite database
+func newTestDB(t *testing.T) *db.Client {
+	t.Helper()
+
+	migrations, err := filepath.Glob("../../../prisma/migrations/*/migration.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	sort.Strings(migrations)
+
+	path := filepath.Join(t.TempDir(), "test.db")
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		migration, err := os.ReadFile(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Exec(string(migration)); err != nil {
+			t.Fatalf("failed to apply %s: %v", m, err)
+		}
+	}
+	conn.Close()
+
+	dbClient, err := db.NewClient("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbClient.Close() })
+	return dbClient
+}
+
+// seedStations stores the fixture's matchable stations, and an old and a recent
+// ridership row for Clark/Lake so pruning has something to delete and keep
+func seedStations(t *testing.T, dbClient *db.Client) {
+	t.Helper()
+
+	cityID, err := dbClient.GetCityID(cityCode, cityName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stations := []struct {
+		externalID, name, lines string
+		lat, lon                float64
+	}{
+		{"40380", "Clark/Lake", `["Blue","Brown","Green","Orange","Pink","Purple"]`, 41.885737, -87.630886},
+		{"40900", "Howard", `["Red","Purple","Yellow"]`, 42.019063, -87.672892},
+	}
+	for _, s := range stations {
+		if err := dbClient.UpsertStation(cityID, s.externalID, s.name, s.lat, s.lon, s.lines); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clarkLake := stationID(t, dbClient, "Clark/Lake")
+	_, err = dbClient.InsertRidershipDailyBatch([]db.RidershipRecord{
+		{StationID: clarkLake, ServiceDate: "2023-01-15T00:00:00Z", Entries: 9000, DayType: db.DayTypeWeekday},
+		{StationID: clarkLake, ServiceDate: "2024-05-01T00:00:00Z", Entries: 11000, DayType: db.DayTypeWeekday},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stationID(t *testing.T, dbClient *db.Client, name string) string {
+	t.Helper()
+	rows, err := dbClient.Query("SELECT id FROM Station WHERE name = ?", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatalf("station %s not found", name)
+	}
+	var id string
+	if err := rows.Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// ridershipDates returns a station's stored service dates, oldest first
+func ridershipDates(t *testing.T, dbClient *db.Client, name string) []string {
+	t.Helper()
+	rows, err := dbClient.Query(`
+		SELECT date(r.serviceDate) FROM RidershipDaily r
+		JOIN Station s ON s.id = r.stationId
+		WHERE s.name = ?
+		ORDER BY r.serviceDate`, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var dates []string
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			t.Fatal(err)
+		}
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// inTempDir runs the test from an empty directory, so the unmatched report is
+// written there and naming data comes from the embedded copies
+func inTempDir(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+// checkSyncResult verifies matching, the unmatched report and pruning after a
+// sync of the fixture pages
+func checkSyncResult(t *testing.T, dbClient *db.Client, dir string) {
+	t.Helper()
+
+	want := map[string][]string{
+		"Clark/Lake": {"2024-05-01", "2024-06-02", "2024-06-03", "2024-06-04"},
+		"Howard":     {"2024-06-02", "2024-06-03", "2024-06-04"},
+	}
+	for name, dates := range want {
+		got := ridershipDates(t, dbClient, name)
+		if len(got) != len(dates) {
+			t.Errorf("%s: got dates %v, want %v", name, got, dates)
+			continue
+		}
+		for i := range dates {
+			if got[i] != dates[i] {
+				t.Errorf("%s: got dates %v, want %v", name, got, dates)
+				break
+			}
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "docs", "unmatched_socrata.csv"))
+	if err != nil {
+		t.Fatalf("unmatched report not written: %v", err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][1] != "Madison/Wabash" || rows[1][4] != "3" {
+		t.Errorf("unmatched report: got %v, want Madison/Wabash with 3 occurrences", rows[1:])
+	}
+}
+
+func TestSyncRidershipReplay(t *testing.T) {
+	fixtures, err := filepath.Abs(fixtureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbClient := newTestDB(t)
+	seedStations(t, dbClient)
+	dir := inTempDir(t)
+
+	// As left by the --record run; adding it to the query would miss the fixtures
+	const watermark = "2024-06-10T08:00:00.000Z"
+	cityID, err := dbClient.GetCityID(cityCode, cityName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbClient.SaveSyncWatermark(cityID, ridershipDataset.ID, watermark); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := SyncOpts{
+		Days:       365,
+		Limit:      4,
+		Since:      "2024-06-01",
+		FixtureDir: fixtures,
+	}
+	if err := SyncRidership(dbClient, "", opts); err != nil {
+		t.Fatalf("SyncRidership: %v", err)
+	}
+	checkSyncResult(t, dbClient, dir)
+
+	if w, err := dbClient.GetSyncWatermark(cityID, ridershipDataset.ID); err != nil || w != watermark {
+		t.Errorf("watermark after replay: got %q (%v), want %q", w, err, watermark)
+	}
+}
+
+func TestSyncRidershipBaseURL(t *testing.T) {
+	fixtures, err := filepath.Abs(fixtureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Serve the recorded pages over HTTP, as a local mirror would
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, socrata.FixturePath(fixtures, r.URL))
+	}))
+	defer server.Close()
+
+	dbClient := newTestDB(t)
+	seedStations(t, dbClient)
+	dir := inTempDir(t)
+
+	opts := SyncOpts{
+		Days:    365,
+		Limit:   4,
+		Since:   "2024-06-01",
+		BaseURL: server.URL,
+	}
+	if err := SyncRidership(dbClient, "", opts); err != nil {
+		t.Fatalf("SyncRidership: %v", err)
+	}
+	checkSyncResult(t, dbClient, dir)
+
+	// Rerunning the same pages changes nothing
+	if err := SyncRidership(dbClient, "", opts); err != nil {
+		t.Fatalf("second SyncRidership: %v", err)
+	}
+	checkSyncResult(t, dbClient, dir)
+}
+
+func TestSyncRidershipResume(t *testing.T) {
+	fixtures, err := filepath.Abs(fixtureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Serve the recorded pages, failing the second one until the first sync gives up
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing && r.URL.Query().Get("$offset") == "4" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		http.ServeFile(w, r, socrata.FixturePath(fixtures, r.URL))
+	}))
+	defer server.Close()
+
+	dbClient := newTestDB(t)
+	seedStations(t, dbClient)
+	dir := inTempDir(t)
+
+	opts := SyncOpts{
+		Days:    365,
+		Limit:   4,
+		Since:   "2024-06-01",
+		BaseURL: server.URL,
+	}
+	if err := SyncRidership(dbClient, "", opts); err == nil {
+		t.Fatal("SyncRidership: expected the second page to fail")
+	}
+
+	// The resumed sync reports the unmatched rows from the first page as well
+	failing = false
+	if err := SyncRidership(dbClient, "", opts); err != nil {
+		t.Fatalf("resumed SyncRidership: %v", err)
+	}
+	checkSyncResult(t, dbClient, dir)
+}
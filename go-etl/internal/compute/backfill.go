@@ -0,0 +1,103 @@
+package compute
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// Backfill step sizes
+const (
+	StepDay   = "day"
+	StepWeek  = "week"
+	StepMonth = "month"
+)
+
+// BackfillScores recomputes ghost scores as if each date from..to were "today",
+// writing one StationMetricsHistory snapshot per station per date. Current
+// StationMetrics and score explanations are left untouched.
+func BackfillScores(dbClient *db.Client, cityCode string, opts Options, from, to time.Time, step string) error {
+	model := opts.Model
+	if model == nil {
+		model = DefaultModel()
+	}
+	if to.Before(from) {
+		return fmt.Errorf("--to (%s) is before --from (%s)", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	nth, err := stepFunc(step)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backfilling %s ghost scores from %s to %s by %s (model %s)\n",
+		cityCode, from.Format("2006-01-02"), to.Format("2006-01-02"), step, model.ID())
+
+	dates := 0
+	snapshots := 0
+	for i, asOf := 0, from; !asOf.After(to); i, asOf = i+1, nth(from, i+1) {
+		metrics, err := dbClient.GetStationMetricsAsOf(cityCode, asOf)
+		if err != nil {
+			return fmt.Errorf("failed to get station metrics as of %s: %w", asOf.Format("2006-01-02"), err)
+		}
+		if len(metrics) == 0 {
+			return fmt.Errorf("no stations found for city: %s", cityCode)
+		}
+
+		// Models may score on trend inputs, so compute them as of this date too
+		if err := addTrends(dbClient, cityCode, metrics, opts.BaselineYear); err != nil {
+			return fmt.Errorf("failed to compute trends as of %s: %w", asOf.Format("2006-01-02"), err)
+		}
+
+		withData, unscored, _ := scoreStations(metrics, model)
+		if len(withData) == 0 {
+			fmt.Printf("%s: no ridership data, skipped\n", asOf.Format("2006-01-02"))
+			continue
+		}
+
+		closed := 0
+		for _, m := range append(withData, unscored...) {
+			if m.DataStatus == "closed" {
+				closed++
+			}
+			if err := dbClient.InsertMetricsSnapshot(m); err != nil {
+				return fmt.Errorf("failed to record snapshot for %s as of %s: %w", m.Name, asOf.Format("2006-01-02"), err)
+			}
+			snapshots++
+		}
+		dates++
+
+		fmt.Printf("%s: scored %d stations (%d missing data, %d closed)\n",
+			asOf.Format("2006-01-02"), len(withData), len(unscored)-closed, closed)
+	}
+
+	fmt.Printf("\nBackfilled %d dates, %d snapshots\n", dates, snapshots)
+	return nil
+}
+
+// stepFunc returns the function giving the i-th as-of date after start. Each date
+// is stepped from start rather than from the previous date, so month steps from the
+// 31st land on each month's last day instead of drifting.
+func stepFunc(step string) (func(start time.Time, i int) time.Time, error) {
+	switch step {
+	case StepDay:
+		return func(t time.Time, i int) time.Time { return t.AddDate(0, 0, i) }, nil
+	case StepWeek:
+		return func(t time.Time, i int) time.Time { return t.AddDate(0, 0, 7*i) }, nil
+	case StepMonth:
+		return addMonths, nil
+	default:
+		return nil, fmt.Errorf("unknown step %q (use day, week or month)", step)
+	}
+}
+
+// addMonths adds n months to t, clamping the day to the end of a shorter month
+// (Jan 31 + 1 month is Feb 28 or 29, not Mar 3 as with AddDate)
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	if last := time.Date(y, m+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day(); d > last {
+		d = last
+	}
+	return time.Date(y, m+time.Month(n), d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,38 @@
+package compute
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStepFunc(t *testing.T) {
+	tests := []struct {
+		step  string
+		start string
+		want  []string // The as-of dates after start
+	}{
+		{StepDay, "2024-02-28", []string{"2024-02-29", "2024-03-01"}},
+		{StepWeek, "2025-12-29", []string{"2026-01-05", "2026-01-12"}},
+		{StepMonth, "2024-01-31", []string{"2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"}},
+		{StepMonth, "2025-01-31", []string{"2025-02-28", "2025-03-31"}},
+		{StepMonth, "2025-11-30", []string{"2025-12-30", "2026-01-30", "2026-02-28"}},
+		{StepMonth, "2025-03-15", []string{"2025-04-15", "2025-05-15"}},
+	}
+
+	for _, tt := range tests {
+		nth, err := stepFunc(tt.step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, _ := time.Parse("2006-01-02", tt.start)
+		for i, want := range tt.want {
+			if got := nth(start, i+1).Format("2006-01-02"); got != want {
+				t.Errorf("%s step %d from %s: got %s, want %s", tt.step, i+1, tt.start, got, want)
+			}
+		}
+	}
+
+	if _, err := stepFunc("year"); err == nil {
+		t.Error("stepFunc(year): expected an error")
+	}
+}
@@ -0,0 +1,147 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// weekendGhostGap is how many points ghostlier a station must be on weekends than
+// on weekdays to be listed as a weekend ghost
+const weekendGhostGap = 25
+
+// scoreDayTypes scores stations separately for each day type, against other
+// stations on the same day type, and stores the results. Inputs that only exist
+// across day types (trends, weekday/weekend ratio) are unavailable and skipped.
+func scoreDayTypes(dbClient *db.Client, cityCode string, model *Model, windowEnd string) ([]db.DayTypeMetric, error) {
+	dayMetrics, err := dbClient.GetDayTypeMetrics(cityCode, windowEnd, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string][]int)
+	for i, dm := range dayMetrics {
+		byType[dm.DayType] = append(byType[dm.DayType], i)
+	}
+
+	for _, dayType := range db.DayTypes {
+		idx := byType[dayType]
+		metrics := make([]db.StationMetric, len(idx))
+		for j, i := range idx {
+			dm := dayMetrics[i]
+			metrics[j] = db.StationMetric{
+				StationID:            dm.StationID,
+				Name:                 dm.Name,
+				Rolling30dAvg:        dm.Rolling30dAvg,
+				Rolling90dAvg:        dm.Rolling90dAvg,
+				ScheduledTripsPerDay: dm.ScheduledTrips,
+				RidersPerTrain:       dm.RidersPerTrain,
+				DataStatus:           "normal",
+			}
+			if dm.Status != db.StationOpen {
+				metrics[j].DataStatus = "closed"
+			} else if dm.Days90d == 0 {
+				metrics[j].DataStatus = "missing"
+			}
+		}
+
+		withData, unscored, _ := scoreStations(metrics, model)
+		scored := make(map[string]int, len(metrics))
+		for _, m := range append(withData, unscored...) {
+			scored[m.StationID] = m.GhostScore
+		}
+		for _, i := range idx {
+			dayMetrics[i].GhostScore = scored[dayMetrics[i].StationID]
+			dayMetrics[i].ModelVersion = model.ID()
+		}
+	}
+
+	for _, dm := range dayMetrics {
+		if err := dbClient.UpsertDayTypeMetric(dm); err != nil {
+			fmt.Printf("Warning: Failed to update %s metrics for station %s: %v\n",
+				db.DayTypeName(dm.DayType), dm.Name, err)
+		}
+	}
+
+	return dayMetrics, nil
+}
+
+// printWeekendGhosts lists stations that are ghosts on weekends but busy on
+// weekdays, such as Loop commuter stations
+func printWeekendGhosts(dayMetrics []db.DayTypeMetric) {
+	scores := make(map[string]map[string]int)
+	names := make(map[string]string)
+	for _, dm := range dayMetrics {
+		if scores[dm.StationID] == nil {
+			scores[dm.StationID] = make(map[string]int)
+		}
+		scores[dm.StationID][dm.DayType] = dm.GhostScore
+		names[dm.StationID] = dm.Name
+	}
+
+	type weekendGhost struct {
+		name             string
+		weekday, weekend int
+	}
+
+	var ghosts []weekendGhost
+	for id, s := range scores {
+		weekday := s[db.DayTypeWeekday]
+		if weekday < 0 {
+			continue
+		}
+
+		// Average whichever weekend day types have ridership
+		weekend, n := 0, 0
+		for _, dayType := range []string{db.DayTypeSaturday, db.DayTypeSundayHoliday} {
+			if score := s[dayType]; score >= 0 {
+				weekend += score
+				n++
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		weekend /= n
+		if weekend-weekday >= weekendGhostGap {
+			ghosts = append(ghosts, weekendGhost{names[id], weekday, weekend})
+		}
+	}
+	if len(ghosts) == 0 {
+		return
+	}
+
+	sort.Slice(ghosts, func(i, j int) bool {
+		return ghosts[i].weekend-ghosts[i].weekday > ghosts[j].weekend-ghosts[j].weekday
+	})
+
+	fmt.Printf("\nWeekend Ghosts (ghostly on weekends, busy on weekdays):\n")
+	for i := 0; i < 5 && i < len(ghosts); i++ {
+		fmt.Printf("%d. %s - Weekday: %d, Weekend: %d\n",
+			i+1, ghosts[i].name, ghosts[i].weekday, ghosts[i].weekend)
+	}
+}
+
+// PrintDayTypeMetrics writes a station's scores by day type
+func PrintDayTypeMetrics(w io.Writer, metrics []db.DayTypeMetric) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nBy day type:")
+	for _, m := range metrics {
+		if m.GhostScore < 0 {
+			fmt.Fprintf(w, "  %-15s no ridership in the window\n", db.DayTypeName(m.DayType))
+			continue
+		}
+		fmt.Fprintf(w, "  %-15s Ghost Score: %3d  30-day avg: %7.0f  90-day avg: %7.0f (%d days)",
+			db.DayTypeName(m.DayType), m.GhostScore, m.Rolling30dAvg, m.Rolling90dAvg, m.Days90d)
+		if m.ScheduledTrips > 0 {
+			fmt.Fprintf(w, "  %.1f riders/train", m.RidersPerTrain)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,118 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// buildExplanation captures how a station's score was derived so it can be
+// justified later without re-running the computation
+func buildExplanation(metric db.StationMetric, model *Model, factors []FactorResult, rank, peerGroupSize int) db.ScoreExplanation {
+	values := make(map[string]interface{})
+	values["lastDayEntries"] = metric.LastDayEntries
+	if metric.Status != "" && metric.Status != db.StationOpen {
+		values["status"] = metric.Status
+	}
+	for _, name := range InputNames() {
+		if value, ok := inputs[name](metric); ok {
+			values[name] = value
+		} else {
+			values[name] = nil
+		}
+	}
+	inputsJSON, _ := json.Marshal(values)
+
+	if factors == nil {
+		factors = []FactorResult{}
+	}
+	factorsJSON, _ := json.Marshal(factors)
+
+	e := db.ScoreExplanation{
+		StationID:     metric.StationID,
+		StationName:   metric.Name,
+		ModelVersion:  model.ID(),
+		GhostScore:    metric.GhostScore,
+		Rank:          rank,
+		PeerGroupSize: peerGroupSize,
+		WindowEnd:     metric.WindowEnd,
+		Inputs:        string(inputsJSON),
+		Factors:       string(factorsJSON),
+	}
+	if end, err := time.Parse("2006-01-02", metric.WindowEnd); err == nil {
+		e.Window30Start = end.AddDate(0, 0, -30).Format("2006-01-02")
+		e.Window90Start = end.AddDate(0, 0, -90).Format("2006-01-02")
+	}
+	return e
+}
+
+// rankByScore returns each station's 1-based rank, ghostliest first
+func rankByScore(scores []StationScore) map[string]int {
+	order := make([]StationScore, len(scores))
+	copy(order, scores)
+	sortScores(order)
+
+	ranks := make(map[string]int, len(order))
+	for i, s := range order {
+		ranks[s.Metric.StationID] = i + 1
+	}
+	return ranks
+}
+
+// PrintExplanation writes a human-readable breakdown of a station's latest score
+func PrintExplanation(w io.Writer, e db.ScoreExplanation) error {
+	fmt.Fprintf(w, "\n%s\n", e.StationName)
+	if e.GhostScore < 0 {
+		var values map[string]interface{}
+		_ = json.Unmarshal([]byte(e.Inputs), &values)
+		if status, ok := values["status"].(string); ok {
+			fmt.Fprintf(w, "Ghost Score: none (station %s on %s)\n", db.StationStatusName(status), e.WindowEnd)
+		} else {
+			fmt.Fprintf(w, "Ghost Score: none (no ridership data in window ending %s)\n", e.WindowEnd)
+		}
+		fmt.Fprintf(w, "Model: %s, computed %s\n", e.ModelVersion, e.ComputedAt)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Ghost Score: %d (rank %d of %d, 1 = ghostliest)\n", e.GhostScore, e.Rank, e.PeerGroupSize)
+	fmt.Fprintf(w, "Model: %s, computed %s\n", e.ModelVersion, e.ComputedAt)
+	fmt.Fprintf(w, "Windows: 30-day %s to %s, 90-day %s to %s\n",
+		e.Window30Start, e.WindowEnd, e.Window90Start, e.WindowEnd)
+
+	var factors []FactorResult
+	if err := json.Unmarshal([]byte(e.Factors), &factors); err != nil {
+		return fmt.Errorf("failed to parse factors: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n%-22s %-11s %14s %10s %8s %8s\n", "Factor", "Scaling", "Value", "Position", "Weight", "Points")
+	var total float64
+	for _, f := range factors {
+		if !f.Available {
+			fmt.Fprintf(w, "%-22s %-11s %14s %10s %8s %8s\n", f.Input, f.Normalization, "n/a", "-", "-", "-")
+			continue
+		}
+		fmt.Fprintf(w, "%-22s %-11s %14.2f %9.0f%% %7.0f%% %8.1f\n",
+			f.Input, f.Normalization, f.Raw, f.Normalized*100, f.Weight*100, f.Contribution)
+		total += f.Contribution
+	}
+	fmt.Fprintf(w, "%-22s %-11s %14s %10s %8s %8.1f\n", "Total", "", "", "", "", total)
+	fmt.Fprintln(w, "\nPosition is where the station sits among its peers for that factor (100% = busiest).")
+	fmt.Fprintln(w, "Points are how much of the ghost score each factor contributes.")
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(e.Inputs), &values); err == nil {
+		fmt.Fprintln(w, "\nInputs:")
+		for _, name := range append([]string{"lastDayEntries"}, InputNames()...) {
+			if v, ok := values[name].(float64); ok {
+				fmt.Fprintf(w, "  %-20s %.2f\n", name, v)
+			} else {
+				fmt.Fprintf(w, "  %-20s n/a\n", name)
+			}
+		}
+	}
+
+	return nil
+}
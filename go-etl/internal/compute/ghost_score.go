@@ -7,8 +7,24 @@ import (
 	"github.com/nate/ghost-stops/go-etl/internal/db"
 )
 
-// ComputeGhostScores calculates ghost scores for all stations in a city
-func ComputeGhostScores(dbClient *db.Client, cityCode string) error {
+// Options configures a ghost score computation
+type Options struct {
+	Model        *Model // Scoring model; DefaultModel when nil
+	BaselineYear int    // Year recovery ratios compare against; 0 disables them
+}
+
+// DefaultOptions scores with the default model against a 2019 baseline
+func DefaultOptions() Options {
+	return Options{Model: DefaultModel(), BaselineYear: DefaultBaselineYear}
+}
+
+// ComputeGhostScores calculates ghost scores and trend metrics for all stations in a city
+func ComputeGhostScores(dbClient *db.Client, cityCode string, opts Options) error {
+	model := opts.Model
+	if model == nil {
+		model = DefaultModel()
+	}
+
 	// Get all station metrics
 	metrics, err := dbClient.GetStationMetrics(cityCode)
 	if err != nil {
@@ -19,43 +35,14 @@ func ComputeGhostScores(dbClient *db.Client, cityCode string) error {
 		return fmt.Errorf("no stations found for city: %s", cityCode)
 	}
 
-	// Separate stations with data from those with missing data
-	var stationsWithData []db.StationMetric
-	var stationsMissing []db.StationMetric
-
-	for _, m := range metrics {
-		if m.DataStatus == "missing" {
-			stationsMissing = append(stationsMissing, m)
-		} else {
-			stationsWithData = append(stationsWithData, m)
-		}
+	if err := addTrends(dbClient, cityCode, metrics, opts.BaselineYear); err != nil {
+		return fmt.Errorf("failed to compute trends: %w", err)
 	}
 
-	// Sort stations with data by 30-day rolling average (ascending)
-	sort.Slice(stationsWithData, func(i, j int) bool {
-		return stationsWithData[i].Rolling30dAvg < stationsWithData[j].Rolling30dAvg
-	})
-
-	// Calculate ghost scores based on percentile rank (only for stations with data)
-	totalStationsWithData := float64(len(stationsWithData))
-	for i := range stationsWithData {
-		// Calculate percentile (0-1)
-		percentile := float64(i+1) / totalStationsWithData
-
-		// Invert percentile to get ghost score (0-100)
-		// Lower ridership = higher ghost score
-		ghostScore := int(100 - (percentile * 100))
-
-		// Handle edge cases
-		if ghostScore < 0 {
-			ghostScore = 0
-		}
-		if ghostScore > 100 {
-			ghostScore = 100
-		}
-
-		stationsWithData[i].GhostScore = ghostScore
+	stationsWithData, unscored, scores := scoreStations(metrics, model)
+	ranks := rankByScore(scores)
 
+	for i := range scores {
 		// Update database
 		err = dbClient.UpdateStationMetrics(stationsWithData[i])
 		if err != nil {
@@ -63,24 +50,60 @@ func ComputeGhostScores(dbClient *db.Client, cityCode string) error {
 				stationsWithData[i].Name, err)
 			continue
 		}
+
+		if err := dbClient.InsertMetricsSnapshot(stationsWithData[i]); err != nil {
+			fmt.Printf("Warning: Failed to record metrics history for station %s: %v\n",
+				stationsWithData[i].Name, err)
+		}
+
+		explanation := buildExplanation(stationsWithData[i], model, scores[i].Factors,
+			ranks[stationsWithData[i].StationID], len(stationsWithData))
+		if err := dbClient.UpsertScoreExplanation(explanation); err != nil {
+			fmt.Printf("Warning: Failed to store score explanation for station %s: %v\n",
+				stationsWithData[i].Name, err)
+		}
 	}
 
-	// Set ghost score to -1 for stations with missing data (to indicate no score)
-	for i := range stationsMissing {
-		stationsMissing[i].GhostScore = -1
-		err = dbClient.UpdateStationMetrics(stationsMissing[i])
+	// Order from ghostliest to busiest for the summary
+	sortScores(scores)
+	for i := range scores {
+		stationsWithData[i] = scores[i].Metric
+	}
+
+	var stationsMissing, stationsClosed []db.StationMetric
+	for i := range unscored {
+		if unscored[i].DataStatus == "closed" {
+			stationsClosed = append(stationsClosed, unscored[i])
+		} else {
+			stationsMissing = append(stationsMissing, unscored[i])
+		}
+
+		err = dbClient.UpdateStationMetrics(unscored[i])
 		if err != nil {
 			fmt.Printf("Warning: Failed to update metrics for station %s: %v\n",
-				stationsMissing[i].Name, err)
+				unscored[i].Name, err)
 			continue
 		}
+
+		if err := dbClient.InsertMetricsSnapshot(unscored[i]); err != nil {
+			fmt.Printf("Warning: Failed to record metrics history for station %s: %v\n",
+				unscored[i].Name, err)
+		}
+
+		explanation := buildExplanation(unscored[i], model, nil, 0, len(stationsWithData))
+		if err := dbClient.UpsertScoreExplanation(explanation); err != nil {
+			fmt.Printf("Warning: Failed to store score explanation for station %s: %v\n",
+				unscored[i].Name, err)
+		}
 	}
 
 	// Print summary
 	fmt.Printf("\nGhost Score Summary for %s:\n", cityCode)
+	fmt.Printf("Scoring model: %s\n", model.ID())
 	fmt.Printf("Total stations: %d\n", len(metrics))
 	fmt.Printf("Stations with ridership data: %d\n", len(stationsWithData))
 	fmt.Printf("Stations with MISSING data: %d\n", len(stationsMissing))
+	fmt.Printf("Closed stations (not scored): %d\n", len(stationsClosed))
 
 	if len(stationsMissing) > 0 {
 		fmt.Printf("\n⚠️  WARNING: %d stations have NO ridership data in the window!\n", len(stationsMissing))
@@ -93,8 +116,15 @@ func ComputeGhostScores(dbClient *db.Client, cityCode string) error {
 		}
 	}
 
+	if len(stationsClosed) > 0 {
+		fmt.Printf("\nClosed stations:\n")
+		for _, m := range stationsClosed {
+			fmt.Printf("- %s (%s)\n", m.Name, db.StationStatusName(m.Status))
+		}
+	}
+
 	if len(stationsWithData) > 0 {
-		fmt.Printf("\nTop 5 Ghost Stations (lowest ridership):\n")
+		fmt.Printf("\nTop 5 Ghost Stations (highest ghost score):\n")
 		for i := 0; i < 5 && i < len(stationsWithData); i++ {
 			fmt.Printf("%d. %s - Ghost Score: %d (30-day avg: %.0f rides)\n",
 				i+1,
@@ -115,5 +145,125 @@ func ComputeGhostScores(dbClient *db.Client, cityCode string) error {
 		}
 	}
 
+	printGainersLosers(stationsWithData, opts.BaselineYear)
+
+	// Windows end on the same date for every station
+	if windowEnd := metrics[0].WindowEnd; windowEnd != "" {
+		dayMetrics, err := scoreDayTypes(dbClient, cityCode, model, windowEnd)
+		if err != nil {
+			fmt.Printf("Warning: Failed to compute day type scores: %v\n", err)
+		} else {
+			printWeekendGhosts(dayMetrics)
+		}
+	}
+
+	// Stations with scheduled service data, emptiest trains first
+	var withService []db.StationMetric
+	for _, m := range stationsWithData {
+		if m.ScheduledTripsPerDay > 0 {
+			withService = append(withService, m)
+		}
+	}
+	if len(withService) > 0 {
+		sort.Slice(withService, func(i, j int) bool {
+			return withService[i].RidersPerTrain < withService[j].RidersPerTrain
+		})
+
+		fmt.Printf("\nTop 5 Emptiest Trains (riders per scheduled train):\n")
+		for i := 0; i < 5 && i < len(withService); i++ {
+			fmt.Printf("%d. %s - %.1f riders/train (%.0f trains/day)\n",
+				i+1,
+				withService[i].Name,
+				withService[i].RidersPerTrain,
+				withService[i].ScheduledTripsPerDay,
+			)
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// scoreStations splits stations with ridership from those without and scores the
+// former against each other. Stations with missing data or closed on the window
+// end date get -1 (no score) and are left out of the peer group.
+func scoreStations(metrics []db.StationMetric, model *Model) (withData, unscored []db.StationMetric, scores []StationScore) {
+	for _, m := range metrics {
+		m.ModelVersion = model.ID()
+		if m.DataStatus == "missing" || m.DataStatus == "closed" {
+			m.GhostScore = -1
+			unscored = append(unscored, m)
+		} else {
+			withData = append(withData, m)
+		}
+	}
+
+	scores = model.Score(withData)
+	for i := range scores {
+		withData[i].GhostScore = scores[i].Score
+		scores[i].Metric.GhostScore = scores[i].Score
+	}
+
+	return withData, unscored, scores
+}
+
+// sortScores orders stations from ghostliest to busiest
+func sortScores(scores []StationScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Metric.Rolling30dAvg < scores[j].Metric.Rolling30dAvg
+	})
+}
+
+// printGainersLosers lists the stations whose ridership changed most, year over
+// year when that history is retained and by 90-day trend otherwise
+func printGainersLosers(metrics []db.StationMetric, baselineYear int) {
+	type change struct {
+		metric db.StationMetric
+		pct    float64
+	}
+
+	var changes []change
+	label := "year over year"
+	for _, m := range metrics {
+		if m.YoYChangePct != nil {
+			changes = append(changes, change{m, *m.YoYChangePct})
+		}
+	}
+	if len(changes) == 0 {
+		label = "90-day trend, % per month"
+		for _, m := range metrics {
+			if pct, ok := monthlyTrendPct(m); ok {
+				changes = append(changes, change{m, pct})
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].pct > changes[j].pct })
+
+	describe := func(c change) string {
+		s := fmt.Sprintf("%+.1f%%", c.pct)
+		if c.metric.RecoveryRatio != nil {
+			s += fmt.Sprintf(", %.0f%% of %d", *c.metric.RecoveryRatio*100, baselineYear)
+		}
+		return s
+	}
+
+	fmt.Printf("\nBiggest Gainers (%s):\n", label)
+	for i := 0; i < 5 && i < len(changes) && changes[i].pct > 0; i++ {
+		fmt.Printf("%d. %s - %s\n", i+1, changes[i].metric.Name, describe(changes[i]))
+	}
+
+	fmt.Printf("\nBiggest Losers (%s):\n", label)
+	for i := 0; i < 5 && i < len(changes); i++ {
+		c := changes[len(changes)-1-i]
+		if c.pct >= 0 {
+			break
+		}
+		fmt.Printf("%d. %s - %s\n", i+1, c.metric.Name, describe(c))
+	}
+}
@@ -0,0 +1,183 @@
+package compute
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// Normalization methods a factor can use to map raw values onto 0-1
+const (
+	NormPercentile = "percentile"
+	NormZScore     = "zscore"
+	NormMinMax     = "minmax"
+	NormLog        = "log"
+)
+
+// Model is a weighted multi-factor ghost score definition loaded from JSON
+type Model struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Factors []Factor `json:"factors"`
+
+	fingerprint string
+}
+
+// Factor is one weighted input to the ghost score
+type Factor struct {
+	Input         string  `json:"input"`
+	Weight        float64 `json:"weight"`
+	Normalization string  `json:"normalization"`
+	// HigherIsGhostlier flips the factor for inputs where a larger value means
+	// a quieter station. All built-in inputs default to "higher is busier".
+	HigherIsGhostlier bool `json:"higherIsGhostlier,omitempty"`
+}
+
+// input extracts a factor's raw value from a station's metrics; ok is false
+// when the station has no value for it (e.g. no GTFS service data)
+type input func(m db.StationMetric) (value float64, ok bool)
+
+// inputs are the factors a model can reference
+var inputs = map[string]input{
+	"rolling30dAvg": func(m db.StationMetric) (float64, bool) {
+		return m.Rolling30dAvg, true
+	},
+	"rolling90dAvg": func(m db.StationMetric) (float64, bool) {
+		return m.Rolling90dAvg, true
+	},
+	// trend compares the last 30 days against the 90-day average (+0.1 = 10% busier lately)
+	"trend": func(m db.StationMetric) (float64, bool) {
+		if m.Rolling90dAvg <= 0 {
+			return 0, false
+		}
+		return m.Rolling30dAvg/m.Rolling90dAvg - 1, true
+	},
+	"weekdayWeekendRatio": func(m db.StationMetric) (float64, bool) {
+		if m.Weekend90dAvg <= 0 {
+			return 0, false
+		}
+		return m.Weekday90dAvg / m.Weekend90dAvg, true
+	},
+	"ridersPerTrain": func(m db.StationMetric) (float64, bool) {
+		if m.ScheduledTripsPerDay <= 0 {
+			return 0, false
+		}
+		return m.RidersPerTrain, true
+	},
+	// yoyChange is a fraction (-0.2 = 20% quieter than a year ago), as models were
+	// written against; YoYChangePct stores the same change in percent
+	"yoyChange": func(m db.StationMetric) (float64, bool) {
+		pct, ok := optional(m.YoYChangePct)
+		return pct / 100, ok
+	},
+	"slope90d": func(m db.StationMetric) (float64, bool) {
+		return optional(m.Slope90d)
+	},
+	"slope365d": func(m db.StationMetric) (float64, bool) {
+		return optional(m.Slope365d)
+	},
+	"recoveryRatio": func(m db.StationMetric) (float64, bool) {
+		return optional(m.RecoveryRatio)
+	},
+}
+
+func optional(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// DefaultModel reproduces the original score: percentile rank of the 30-day average
+func DefaultModel() *Model {
+	m := &Model{
+		Name:    "default",
+		Version: "1",
+		Factors: []Factor{
+			{Input: "rolling30dAvg", Weight: 1, Normalization: NormPercentile},
+		},
+	}
+	m.fingerprint = fingerprint(m)
+	return m
+}
+
+// LoadModel reads and validates a model definition from a JSON file
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model file: %w", err)
+	}
+
+	var m Model
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse model file %s: %w", path, err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid model %s: %w", path, err)
+	}
+	m.fingerprint = fingerprint(&m)
+
+	return &m, nil
+}
+
+// Validate checks that every factor references a known input and normalization
+func (m *Model) Validate() error {
+	if m.Name == "" || m.Version == "" {
+		return fmt.Errorf("name and version are required")
+	}
+	if len(m.Factors) == 0 {
+		return fmt.Errorf("at least one factor is required")
+	}
+
+	for i := range m.Factors {
+		f := &m.Factors[i]
+		if _, ok := inputs[f.Input]; !ok {
+			return fmt.Errorf("factor %d: unknown input %q (available: %s)", i, f.Input, strings.Join(InputNames(), ", "))
+		}
+		if f.Weight <= 0 {
+			return fmt.Errorf("factor %s: weight must be positive", f.Input)
+		}
+		if f.Normalization == "" {
+			f.Normalization = NormPercentile
+		}
+		switch f.Normalization {
+		case NormPercentile, NormZScore, NormMinMax, NormLog:
+		default:
+			return fmt.Errorf("factor %s: unknown normalization %q", f.Input, f.Normalization)
+		}
+	}
+
+	return nil
+}
+
+// ID identifies the model and the exact definition used, e.g. "default@1+3fa2c1d0".
+// The hash changes whenever the factors do, even if the version wasn't bumped.
+func (m *Model) ID() string {
+	return fmt.Sprintf("%s@%s+%s", m.Name, m.Version, m.fingerprint)
+}
+
+// InputNames lists the inputs a model factor can reference
+func InputNames() []string {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fingerprint(m *Model) string {
+	data, _ := json.Marshal(m.Factors)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:8]
+}
@@ -0,0 +1,97 @@
+package compute
+
+import (
+	"math"
+	"sort"
+)
+
+// normalize maps raw values onto 0-1 where 1 is the busiest station.
+// values and the result are indexed the same way.
+func normalize(method string, values []float64) []float64 {
+	switch method {
+	case NormZScore:
+		return zScore(values)
+	case NormMinMax:
+		return minMax(values)
+	case NormLog:
+		logged := make([]float64, len(values))
+		for i, v := range values {
+			// Sign-preserving so negative inputs like yoyChange still order correctly
+			logged[i] = math.Copysign(math.Log1p(math.Abs(v)), v)
+		}
+		return minMax(logged)
+	default:
+		return percentile(values)
+	}
+}
+
+// percentile ranks values ascending; the lowest gets 1/n and the highest 1
+func percentile(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+
+	out := make([]float64, len(values))
+	n := float64(len(values))
+	for rank, idx := range order {
+		out[idx] = float64(rank+1) / n
+	}
+	return out
+}
+
+// zScore standardizes values and maps them through the normal CDF
+func zScore(values []float64) []float64 {
+	out := make([]float64, len(values))
+	if len(values) == 0 {
+		return out
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+
+	for i, v := range values {
+		if stddev == 0 {
+			out[i] = 0.5
+			continue
+		}
+		z := (v - mean) / stddev
+		out[i] = 0.5 * (1 + math.Erf(z/math.Sqrt2))
+	}
+	return out
+}
+
+// minMax rescales values linearly so the smallest is 0 and the largest 1
+func minMax(values []float64) []float64 {
+	out := make([]float64, len(values))
+	if len(values) == 0 {
+		return out
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	for i, v := range values {
+		if hi == lo {
+			out[i] = 0.5
+			continue
+		}
+		out[i] = (v - lo) / (hi - lo)
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package compute
+
+import "github.com/nate/ghost-stops/go-etl/internal/db"
+
+// FactorResult records how one factor contributed to a station's score
+type FactorResult struct {
+	Input         string  `json:"input"`
+	Normalization string  `json:"normalization"`
+	Available     bool    `json:"available"`    // false when the station had no value for this input
+	Raw           float64 `json:"raw"`          // input value before normalization
+	Normalized    float64 `json:"normalized"`   // 0-1, where 1 is the busiest station in the peer group
+	Weight        float64 `json:"weight"`       // share of the score after dropping unavailable factors
+	Contribution  float64 `json:"contribution"` // ghost score points attributed to this factor
+	PeerCount     int     `json:"peerCount"`    // stations with a value for this input
+}
+
+// StationScore is a station's ghost score with its per-factor breakdown
+type StationScore struct {
+	Metric  db.StationMetric
+	Score   int
+	Factors []FactorResult
+}
+
+// Score applies the model to stations that have ridership data. Each factor is
+// normalized across the stations that have a value for it, then combined by
+// weight; a station missing a factor has the remaining weights rescaled.
+func (m *Model) Score(metrics []db.StationMetric) []StationScore {
+	scores := make([]StationScore, len(metrics))
+	for i := range metrics {
+		scores[i] = StationScore{
+			Metric:  metrics[i],
+			Factors: make([]FactorResult, len(m.Factors)),
+		}
+	}
+
+	for f, factor := range m.Factors {
+		extract := inputs[factor.Input]
+
+		var values []float64
+		var idx []int
+		for i, metric := range metrics {
+			value, ok := extract(metric)
+			scores[i].Factors[f] = FactorResult{
+				Input:         factor.Input,
+				Normalization: factor.Normalization,
+				Available:     ok,
+				Raw:           value,
+			}
+			if ok {
+				values = append(values, value)
+				idx = append(idx, i)
+			}
+		}
+
+		normalized := normalize(factor.Normalization, values)
+		for j, i := range idx {
+			n := normalized[j]
+			if factor.HigherIsGhostlier {
+				n = 1 - n
+			}
+			scores[i].Factors[f].Normalized = n
+			scores[i].Factors[f].PeerCount = len(values)
+		}
+	}
+
+	for i := range scores {
+		var totalWeight float64
+		for f, factor := range m.Factors {
+			if scores[i].Factors[f].Available {
+				totalWeight += factor.Weight
+			}
+		}
+
+		if totalWeight == 0 {
+			scores[i].Score = -1
+			continue
+		}
+
+		// Ghost score is the inverse of the weighted "busyness"
+		var combined float64
+		for f, factor := range m.Factors {
+			result := &scores[i].Factors[f]
+			if !result.Available {
+				continue
+			}
+			result.Weight = factor.Weight / totalWeight
+			result.Contribution = result.Weight * (1 - result.Normalized) * 100
+			combined += result.Weight * result.Normalized
+		}
+
+		ghostScore := int(100 - (combined * 100))
+
+		// Handle edge cases
+		if ghostScore < 0 {
+			ghostScore = 0
+		}
+		if ghostScore > 100 {
+			ghostScore = 100
+		}
+		scores[i].Score = ghostScore
+	}
+
+	return scores
+}
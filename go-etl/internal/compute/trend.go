@@ -0,0 +1,98 @@
+package compute
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// DefaultBaselineYear is the pre-pandemic year recovery ratios compare against
+const DefaultBaselineYear = 2019
+
+// Minimum days of data before a slope is meaningful
+const (
+	minSlopePoints90  = 30
+	minSlopePoints365 = 120
+)
+
+// addTrends fills in slopes and recovery ratios for stations with data. All
+// windows end on each station's WindowEnd, so this works for backfills too.
+func addTrends(dbClient *db.Client, cityCode string, metrics []db.StationMetric, baselineYear int) error {
+	if len(metrics) == 0 || metrics[0].WindowEnd == "" {
+		return nil
+	}
+
+	end, err := time.Parse("2006-01-02", metrics[0].WindowEnd)
+	if err != nil {
+		return fmt.Errorf("invalid window end %q: %w", metrics[0].WindowEnd, err)
+	}
+
+	series, err := dbClient.GetDailyRidershipSeries(cityCode, end.AddDate(0, 0, -365).Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("failed to load ridership series: %w", err)
+	}
+
+	var baseline map[string]float64
+	if baselineYear > 0 {
+		baselineEnd := end.AddDate(baselineYear-end.Year(), 0, 0)
+		baseline, err = dbClient.GetAverageEntriesBetween(cityCode,
+			baselineEnd.AddDate(0, 0, -30).Format("2006-01-02"), baselineEnd.Format("2006-01-02"))
+		if err != nil {
+			return fmt.Errorf("failed to load %d baseline: %w", baselineYear, err)
+		}
+	}
+
+	for i := range metrics {
+		m := &metrics[i]
+		entries := series[m.StationID]
+
+		m.Slope90d = slopeSince(entries, end, 90, minSlopePoints90)
+		m.Slope365d = slopeSince(entries, end, 365, minSlopePoints365)
+
+		if avg, ok := baseline[m.StationID]; ok && avg > 0 {
+			ratio := m.Rolling30dAvg / avg
+			m.RecoveryRatio = &ratio
+			m.RecoveryBaselineYear = baselineYear
+		}
+	}
+
+	return nil
+}
+
+// slopeSince fits a least-squares line to the last `days` days of entries up to
+// end and returns its slope in entries per day, or nil with too few points
+func slopeSince(entries []db.DailyEntry, end time.Time, days, minPoints int) *float64 {
+	start := end.AddDate(0, 0, -days)
+
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, e := range entries {
+		date, err := time.Parse("2006-01-02", e.ServiceDate)
+		if err != nil || date.Before(start) || date.After(end) {
+			continue
+		}
+		x := date.Sub(start).Hours() / 24
+		y := float64(e.Entries)
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < float64(minPoints) || denominator == 0 {
+		return nil
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	return &slope
+}
+
+// monthlyTrendPct expresses the 90-day slope as percent change per 30 days
+func monthlyTrendPct(m db.StationMetric) (float64, bool) {
+	if m.Slope90d == nil || m.Rolling90dAvg <= 0 {
+		return 0, false
+	}
+	return *m.Slope90d * 30 / m.Rolling90dAvg * 100, true
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -79,7 +80,7 @@ func (c *Client) UpsertStation(cityID, externalID, name string, lat, lon float64
 	// Try to update existing station
 	result, err := c.db.Exec(`
 		UPDATE Station
-		SET name = ?, latitude = ?, longitude = ?, lines = ?
+		SET name = ?, latitude = ?, longitude = ?, lines = ?, active = 1, deactivatedAt = NULL
 		WHERE cityId = ? AND externalId = ?`,
 		name, lat, lon, lines, cityID, externalID,
 	)
@@ -105,6 +106,136 @@ func (c *Client) UpsertStation(cityID, externalID, name string, lat, lon float64
 	return nil
 }
 
+// StoredStation is a GTFS station as currently stored
+type StoredStation struct {
+	ID         string
+	ExternalID string
+	Name       string
+	Lat        float64
+	Lon        float64
+	Lines      string // JSON array
+	Active     bool
+}
+
+// GetGTFSStations returns a city's stations that came from a GTFS feed
+func (c *Client) GetGTFSStations(cityID string) ([]StoredStation, error) {
+	rows, err := c.db.Query(`
+		SELECT id, externalId, name, latitude, longitude, lines, active
+		FROM Station
+		WHERE cityId = ? AND externalId IS NOT NULL`,
+		cityID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stations: %w", err)
+	}
+	defer rows.Close()
+
+	var stations []StoredStation
+	for rows.Next() {
+		var s StoredStation
+		if err := rows.Scan(&s.ID, &s.ExternalID, &s.Name, &s.Lat, &s.Lon, &s.Lines, &s.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan station: %w", err)
+		}
+		stations = append(stations, s)
+	}
+
+	return stations, rows.Err()
+}
+
+// DeactivateStation marks a station as no longer in service. Its history is kept,
+// but it is left out of ghost score computation, and its current metrics are
+// marked closed so its last ghost score isn't served.
+func (c *Client) DeactivateStation(stationID string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE Station
+		SET active = 0, deactivatedAt = datetime('now')
+		WHERE id = ? AND active = 1`,
+		stationID,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to deactivate station: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE StationMetrics
+		SET dataStatus = 'closed', ghostScore = -1
+		WHERE stationId = ?`,
+		stationID,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to close station metrics: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Route represents a transit route (line) from a GTFS feed
+type Route struct {
+	ExternalID string // GTFS route_id
+	Name       string
+	Color      string
+	TextColor  string
+	RouteType  int
+}
+
+// UpsertRoute creates or updates a route for a city
+func (c *Client) UpsertRoute(cityID string, r Route) error {
+	_, err := c.db.Exec(`
+		INSERT INTO Route (id, cityId, externalId, name, color, textColor, routeType)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(cityId, externalId) DO UPDATE SET
+		name = excluded.name,
+		color = excluded.color,
+		textColor = excluded.textColor,
+		routeType = excluded.routeType`,
+		cityID, r.ExternalID, r.Name, r.Color, r.TextColor, r.RouteType,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert route: %w", err)
+	}
+	return nil
+}
+
+// StationService holds the scheduled rail trips stopping at a station per day type
+type StationService struct {
+	StationID     string
+	WeekdayTrips  float64
+	SaturdayTrips float64
+	SundayTrips   float64
+	FeedStartDate string
+	FeedEndDate   string
+}
+
+// UpsertStationService creates or updates a station's scheduled service frequency
+func (c *Client) UpsertStationService(s StationService) error {
+	_, err := c.db.Exec(`
+		INSERT INTO StationService (
+			id, stationId, weekdayTrips, saturdayTrips, sundayTrips,
+			feedStartDate, feedEndDate, lastUpdated
+		) VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(stationId) DO UPDATE SET
+		weekdayTrips = excluded.weekdayTrips,
+		saturdayTrips = excluded.saturdayTrips,
+		sundayTrips = excluded.sundayTrips,
+		feedStartDate = excluded.feedStartDate,
+		feedEndDate = excluded.feedEndDate,
+		lastUpdated = excluded.lastUpdated`,
+		s.StationID, s.WeekdayTrips, s.SaturdayTrips, s.SundayTrips,
+		s.FeedStartDate, s.FeedEndDate,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert station service: %w", err)
+	}
+	return nil
+}
+
 // GetStationIDByExternalID finds a station's UUID by its external (GTFS) ID.
 func (c *Client) GetStationIDByExternalID(cityID, externalID string) (string, error) {
 	var id string
@@ -139,6 +270,16 @@ func (c *Client) GetStationIDByCtaStationId(cityID, ctaStationId string) (string
 	return id, nil
 }
 
+// GetStationCtaStationId returns a station's CTA station ID, or "" when it has none
+func (c *Client) GetStationCtaStationId(stationID string) (string, error) {
+	var ctaStationId sql.NullString
+	err := c.db.QueryRow("SELECT ctaStationId FROM Station WHERE id = ?", stationID).Scan(&ctaStationId)
+	if err != nil {
+		return "", fmt.Errorf("failed to query CTA station ID: %w", err)
+	}
+	return ctaStationId.String, nil
+}
+
 // UpdateStationCtaStationId updates a station's CTA station ID.
 func (c *Client) UpdateStationCtaStationId(stationID, ctaStationId string) error {
 	_, err := c.db.Exec(`
@@ -153,52 +294,6 @@ func (c *Client) UpdateStationCtaStationId(stationID, ctaStationId string) error
 	return nil
 }
 
-// GetStationIDByName finds a station ID by city and normalized name
-func (c *Client) GetStationIDByName(cityID, normalizedName string) (string, error) {
-	var id string
-
-	// First try exact match on normalized name in StationAlias
-	err := c.db.QueryRow(`
-		SELECT s.id
-		FROM Station s
-		JOIN StationAlias sa ON sa.stationId = s.id
-		WHERE s.cityId = ? AND sa.normalized = ?
-		LIMIT 1`,
-		cityID, normalizedName,
-	).Scan(&id)
-
-	if err == nil {
-		return id, nil
-	}
-
-	// If no alias found, try direct station name match
-	// We'll need to normalize station names for comparison
-	rows, err := c.db.Query(`
-		SELECT id, name
-		FROM Station
-		WHERE cityId = ?`,
-		cityID,
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed to query stations: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var stationID, stationName string
-		if err := rows.Scan(&stationID, &stationName); err != nil {
-			continue
-		}
-
-		// Normalize and compare
-		if NormalizeStationName(stationName) == normalizedName {
-			return stationID, nil
-		}
-	}
-
-	return "", sql.ErrNoRows
-}
-
 // CreateStationAlias creates an alias for a station
 func (c *Client) CreateStationAlias(stationID, aliasName, normalized string) error {
 	_, err := c.db.Exec(`
@@ -210,8 +305,50 @@ func (c *Client) CreateStationAlias(stationID, aliasName, normalized string) err
 }
 
 
+// StationAliases maps a city's normalized aliases to stations. A normalized alias
+// shared by several stations is kept in Ambiguous instead, so that no caller
+// silently attributes it to one of them.
+type StationAliases struct {
+	Unique    map[string]string   // Normalized alias -> station ID
+	Ambiguous map[string][]string // Normalized alias -> station IDs, sorted
+}
+
+// Add records that a normalized alias refers to a station, moving it to Ambiguous
+// when it already refers to another
+func (a StationAliases) Add(normalized, stationID string) {
+	if ids, ok := a.Ambiguous[normalized]; ok {
+		for _, id := range ids {
+			if id == stationID {
+				return
+			}
+		}
+		ids = append(ids, stationID)
+		sort.Strings(ids)
+		a.Ambiguous[normalized] = ids
+		return
+	}
+
+	existing, ok := a.Unique[normalized]
+	if !ok {
+		a.Unique[normalized] = stationID
+		return
+	}
+	if existing == stationID {
+		return
+	}
+	delete(a.Unique, normalized)
+	ids := []string{existing, stationID}
+	sort.Strings(ids)
+	a.Ambiguous[normalized] = ids
+}
+
 // GetAllStationAliases retrieves all aliases for a given city
-func (c *Client) GetAllStationAliases(cityID string) (map[string]string, error) {
+func (c *Client) GetAllStationAliases(cityID string) (StationAliases, error) {
+	aliases := StationAliases{
+		Unique:    make(map[string]string),
+		Ambiguous: make(map[string][]string),
+	}
+
 	rows, err := c.db.Query(`
 		SELECT sa.normalized, sa.stationId
 		FROM StationAlias sa
@@ -220,64 +357,123 @@ func (c *Client) GetAllStationAliases(cityID string) (map[string]string, error)
 		cityID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query aliases: %w", err)
+		return aliases, fmt.Errorf("failed to query aliases: %w", err)
 	}
 	defer rows.Close()
 
-	aliases := make(map[string]string)
 	for rows.Next() {
 		var normalized, stationID string
 		if err := rows.Scan(&normalized, &stationID); err != nil {
-			return nil, fmt.Errorf("failed to scan alias row: %w", err)
+			return aliases, fmt.Errorf("failed to scan alias row: %w", err)
 		}
-		aliases[normalized] = stationID
+		aliases.Add(normalized, stationID)
 	}
 
-	return aliases, nil
+	return aliases, rows.Err()
 }
 
+// StationAliasRecord is a stored alias with its station
+type StationAliasRecord struct {
+	ID          string
+	StationID   string
+	StationName string
+	AliasName   string
+	Normalized  string
+}
 
-// GetAllStationNames retrieves all station names for a given city
-func (c *Client) GetAllStationNames(cityID string) ([]string, error) {
-	rows, err := c.db.Query("SELECT name FROM Station WHERE cityId = ?", cityID)
+// GetStationAliasRecords lists a city's aliases ordered by station and alias name
+func (c *Client) GetStationAliasRecords(cityID string) ([]StationAliasRecord, error) {
+	rows, err := c.db.Query(`
+		SELECT sa.id, sa.stationId, s.name, sa.aliasName, sa.normalized
+		FROM StationAlias sa
+		JOIN Station s ON s.id = sa.stationId
+		WHERE s.cityId = ?
+		ORDER BY s.name, sa.aliasName`, cityID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query station names: %w", err)
+		return nil, fmt.Errorf("failed to query station aliases: %w", err)
 	}
 	defer rows.Close()
 
-	var names []string
+	var aliases []StationAliasRecord
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
-			return nil, fmt.Errorf("failed to scan station name: %w", err)
+		var a StationAliasRecord
+		if err := rows.Scan(&a.ID, &a.StationID, &a.StationName, &a.AliasName, &a.Normalized); err != nil {
+			return nil, fmt.Errorf("failed to scan station alias: %w", err)
 		}
-		names = append(names, name)
+		aliases = append(aliases, a)
 	}
 
-	return names, nil
+	return aliases, rows.Err()
+}
+
+// UpdateStationAliasNormalized rewrites the normalized form of the given aliases
+// (alias ID -> normalized) in one transaction
+func (c *Client) UpdateStationAliasNormalized(normalized map[string]string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`UPDATE StationAlias SET normalized = ? WHERE id = ?`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for id, value := range normalized {
+		if _, err := stmt.Exec(value, id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update alias %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
-// GetStationNamesAndIDs retrieves all station IDs and names for a given city
-func (c *Client) GetStationNamesAndIDs(cityID string) (map[string]string, error) {
-	rows, err := c.db.Query("SELECT id, name FROM Station WHERE cityId = ?", cityID)
+// GetStations lists a city's stations ordered by name
+func (c *Client) GetStations(cityID string) ([]StationRef, error) {
+	rows, err := c.db.Query("SELECT id, name FROM Station WHERE cityId = ? ORDER BY name", cityID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query stations: %w", err)
 	}
 	defer rows.Close()
 
-	stations := make(map[string]string) // normalized name -> station ID
+	var stations []StationRef
 	for rows.Next() {
-		var id, name string
-		if err := rows.Scan(&id, &name); err != nil {
+		var s StationRef
+		if err := rows.Scan(&s.ID, &s.Name); err != nil {
 			return nil, fmt.Errorf("failed to scan station: %w", err)
 		}
-		normalized := NormalizeStationName(name)
-		stations[normalized] = id
+		stations = append(stations, s)
 	}
 
 	return stations, rows.Err()
 }
 
+// GetAllStationNames retrieves all station names for a given city
+func (c *Client) GetAllStationNames(cityID string) ([]string, error) {
+	rows, err := c.db.Query("SELECT name FROM Station WHERE cityId = ?", cityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query station names: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan station name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
 // GetStationCountWithRidershipInWindow returns the number of stations with ridership data in the given window
 func (c *Client) GetStationCountWithRidershipInWindow(cityID string, days int) (int, error) {
 	query := `
@@ -304,36 +500,82 @@ type RidershipRecord struct {
 	StationID   string
 	ServiceDate string
 	Entries     int
+	DayType     string // "W", "A" or "U"; empty when the source doesn't say
+}
+
+// RidershipUpsertStats counts what a batch upsert did to existing rows
+type RidershipUpsertStats struct {
+	Inserted  int // New station/date rows
+	Updated   int // Existing rows whose entries or day type changed
+	Unchanged int // Existing rows that already had identical values
 }
 
-// InsertRidershipDailyBatch inserts multiple ridership records in a transaction
-func (c *Client) InsertRidershipDailyBatch(records []RidershipRecord) error {
+// Add accumulates another batch's counts
+func (s *RidershipUpsertStats) Add(o RidershipUpsertStats) {
+	s.Inserted += o.Inserted
+	s.Updated += o.Updated
+	s.Unchanged += o.Unchanged
+}
+
+// InsertRidershipDailyBatch upserts multiple ridership records in a transaction,
+// leaving rows whose values are unchanged untouched
+func (c *Client) InsertRidershipDailyBatch(records []RidershipRecord) (RidershipUpsertStats, error) {
+	var stats RidershipUpsertStats
+
 	tx, err := c.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return stats, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	existing, err := tx.Prepare(`
+		SELECT 1 FROM RidershipDaily WHERE stationId = ? AND serviceDate = ?`)
+	if err != nil {
+		tx.Rollback()
+		return stats, fmt.Errorf("failed to prepare statement: %w", err)
 	}
+	defer existing.Close()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO RidershipDaily (id, stationId, serviceDate, entries)
-		VALUES (lower(hex(randomblob(16))), ?, ?, ?)
+		INSERT INTO RidershipDaily (id, stationId, serviceDate, entries, dayType)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?)
 		ON CONFLICT(stationId, serviceDate) DO UPDATE SET
-		entries = excluded.entries
+		entries = excluded.entries,
+		dayType = COALESCE(excluded.dayType, RidershipDaily.dayType)
+		WHERE RidershipDaily.entries != excluded.entries
+		OR RidershipDaily.dayType IS NOT COALESCE(excluded.dayType, RidershipDaily.dayType)
 	`)
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return stats, fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, r := range records {
-		_, err := stmt.Exec(r.StationID, r.ServiceDate, r.Entries)
+		var found int
+		err := existing.QueryRow(r.StationID, r.ServiceDate).Scan(&found)
+		if err != nil && err != sql.ErrNoRows {
+			tx.Rollback()
+			return stats, fmt.Errorf("failed to look up ridership for station %s on %s: %w", r.StationID, r.ServiceDate, err)
+		}
+		exists := err == nil
+
+		result, err := stmt.Exec(r.StationID, r.ServiceDate, r.Entries, nullIfEmpty(r.DayType))
 		if err != nil {
 			tx.Rollback()
-			return fmt.Errorf("failed to execute statement for station %s on %s: %w", r.StationID, r.ServiceDate, err)
+			return stats, fmt.Errorf("failed to execute statement for station %s on %s: %w", r.StationID, r.ServiceDate, err)
+		}
+
+		switch affected, _ := result.RowsAffected(); {
+		case !exists:
+			stats.Inserted++
+		case affected > 0:
+			stats.Updated++
+		default:
+			stats.Unchanged++
 		}
 	}
 
-	return tx.Commit()
+	return stats, tx.Commit()
 }
 
 // PruneRidership deletes ridership data older than a given number of days relative to the max service date
@@ -469,9 +711,22 @@ func (c *Client) GetMaxServiceDate(cityCode string) (time.Time, error) {
 
 // GetStationMetrics retrieves metrics for all stations in a city
 func (c *Client) GetStationMetrics(cityCode string) ([]StationMetric, error) {
+	return c.GetStationMetricsAsOf(cityCode, time.Time{})
+}
+
+// GetStationMetricsAsOf retrieves metrics with rolling windows ending on asOf,
+// ignoring any ridership after it, for the stations not yet deactivated then. A
+// zero asOf anchors on the latest service date and leaves out every deactivated
+// station.
+func (c *Client) GetStationMetricsAsOf(cityCode string, asOf time.Time) ([]StationMetric, error) {
+	var asOfArg interface{}
+	if !asOf.IsZero() {
+		asOfArg = asOf.Format("2006-01-02")
+	}
+
 	query := `
 		WITH MaxDate AS (
-			SELECT MAX(serviceDate) as maxDate
+			SELECT COALESCE(?, MAX(serviceDate)) as maxDate
 			FROM RidershipDaily
 		),
 		RollingAverages AS (
@@ -487,12 +742,31 @@ func (c *Client) GetStationMetrics(cityCode string) ([]StationMetric, error) {
 					WHEN rd.serviceDate >= date((SELECT maxDate FROM MaxDate), '-90 days')
 					THEN rd.entries
 				END) as rolling90dAvg,
+				AVG(CASE
+					WHEN rd.serviceDate >= date((SELECT maxDate FROM MaxDate), '-90 days')
+					AND ` + dayTypeExpr + ` = 'W'
+					THEN rd.entries
+				END) as weekday90dAvg,
+				AVG(CASE
+					WHEN rd.serviceDate >= date((SELECT maxDate FROM MaxDate), '-90 days')
+					AND ` + dayTypeExpr + ` IN ('A', 'U')
+					THEN rd.entries
+				END) as weekend90dAvg,
+				AVG(CASE
+					WHEN date(rd.serviceDate) >= date((SELECT maxDate FROM MaxDate), '-395 days')
+					AND date(rd.serviceDate) < date((SELECT maxDate FROM MaxDate), '-365 days')
+					THEN rd.entries
+				END) as priorYear30dAvg,
 				MAX(rd.entries) as lastDayEntries,
-				MAX(rd.serviceDate) as serviceDateMax
+				MAX(rd.serviceDate) as serviceDateMax,
+				(5 * ss.weekdayTrips + ss.saturdayTrips + ss.sundayTrips) / 7.0 as scheduledTripsPerDay,
+				` + stationStatusAt("(SELECT maxDate FROM MaxDate)") + ` as status
 			FROM Station s
 			JOIN City c ON c.id = s.cityId
 			LEFT JOIN RidershipDaily rd ON rd.stationId = s.id
-			WHERE c.code = ?
+				AND date(rd.serviceDate) <= date((SELECT maxDate FROM MaxDate))
+			LEFT JOIN StationService ss ON ss.stationId = s.id
+			WHERE c.code = ? AND ` + stationInServiceOn("COALESCE(?, 'now')") + `
 			GROUP BY s.id, s.name
 		)
 		SELECT
@@ -503,13 +777,20 @@ func (c *Client) GetStationMetrics(cityCode string) ([]StationMetric, error) {
 			COALESCE(lastDayEntries, 0) as lastDayEntries,
 			serviceDateMax,
 			CASE
+				WHEN status != 'open' THEN 'closed'
 				WHEN ridershipCount = 0 THEN 'missing'
 				ELSE 'normal'
-			END as dataStatus
+			END as dataStatus,
+			status,
+			COALESCE(scheduledTripsPerDay, 0) as scheduledTripsPerDay,
+			COALESCE(weekday90dAvg, 0) as weekday90dAvg,
+			COALESCE(weekend90dAvg, 0) as weekend90dAvg,
+			COALESCE(priorYear30dAvg, 0) as priorYear30dAvg,
+			COALESCE(date((SELECT maxDate FROM MaxDate)), '') as windowEnd
 		FROM RollingAverages
 		ORDER BY rolling30dAvg ASC`
 
-	rows, err := c.db.Query(query, cityCode)
+	rows, err := c.db.Query(query, asOfArg, cityCode, asOfArg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query metrics: %w", err)
 	}
@@ -528,6 +809,12 @@ func (c *Client) GetStationMetrics(cityCode string) ([]StationMetric, error) {
 			&m.LastDayEntries,
 			&serviceDateMax,
 			&m.DataStatus,
+			&m.Status,
+			&m.ScheduledTripsPerDay,
+			&m.Weekday90dAvg,
+			&m.Weekend90dAvg,
+			&m.PriorYear30dAvg,
+			&m.WindowEnd,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -537,6 +824,16 @@ func (c *Client) GetStationMetrics(cityCode string) ([]StationMetric, error) {
 			m.ServiceDateMax = serviceDateMax.String
 		}
 
+		// Riders per scheduled train separates "few trains" from "empty trains"
+		if m.ScheduledTripsPerDay > 0 {
+			m.RidersPerTrain = m.Rolling30dAvg / m.ScheduledTripsPerDay
+		}
+
+		if m.PriorYear30dAvg > 0 {
+			yoy := (m.Rolling30dAvg - m.PriorYear30dAvg) / m.PriorYear30dAvg * 100
+			m.YoYChangePct = &yoy
+		}
+
 		metrics = append(metrics, m)
 	}
 
@@ -548,21 +845,49 @@ func (c *Client) UpdateStationMetrics(m StationMetric) error {
 	_, err := c.db.Exec(`
 		INSERT OR REPLACE INTO StationMetrics (
 			id, stationId, lastDayEntries, rolling30dAvg, rolling90dAvg,
-			ghostScore, lastUpdated, serviceDateMax, dataStatus
+			ghostScore, lastUpdated, serviceDateMax, dataStatus, ridersPerTrain,
+			modelVersion, slope90d, slope365d, yoyChangePct, recoveryRatio,
+			recoveryBaselineYear
 		) VALUES (
 			COALESCE(
 				(SELECT id FROM StationMetrics WHERE stationId = ?),
 				lower(hex(randomblob(16)))
 			),
-			?, ?, ?, ?, ?, datetime('now'), ?, ?
+			?, ?, ?, ?, ?, datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?
 		)`,
 		m.StationID,
 		m.StationID, m.LastDayEntries, m.Rolling30dAvg, m.Rolling90dAvg,
-		m.GhostScore, m.ServiceDateMax, m.DataStatus,
+		m.GhostScore, m.ServiceDateMax, m.DataStatus, nullIfNoService(m),
+		m.ModelVersion, m.Slope90d, m.Slope365d, m.YoYChangePct, m.RecoveryRatio,
+		nullIfZero(m.RecoveryBaselineYear),
 	)
 	return err
 }
 
+// nullIfNoService stores NULL rather than 0 riders per train for stations without GTFS service data
+func nullIfNoService(m StationMetric) interface{} {
+	if m.ScheduledTripsPerDay <= 0 {
+		return nil
+	}
+	return m.RidersPerTrain
+}
+
+// nullIfEmpty stores NULL for unset text columns
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullIfZero stores NULL for unset integer columns
+func nullIfZero(v int) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
 // StationMetric represents station ridership metrics
 type StationMetric struct {
 	StationID      string
@@ -572,5 +897,835 @@ type StationMetric struct {
 	Rolling90dAvg  float64
 	GhostScore     int
 	ServiceDateMax string
-	DataStatus     string // "normal", "missing"
-}
\ No newline at end of file
+	DataStatus     string // "normal", "missing" or "closed"
+	Status         string // Lifecycle status on WindowEnd; see StationStatuses
+
+	ScheduledTripsPerDay float64 // From StationService; 0 when the GTFS feed had no calendar
+	RidersPerTrain       float64 // Rolling30dAvg / ScheduledTripsPerDay
+
+	Weekday90dAvg   float64 // Weekday (day type W) average over the 90-day window
+	Weekend90dAvg   float64 // Saturday, Sunday and holiday average over the 90-day window
+	PriorYear30dAvg float64 // Same 30-day window one year earlier; 0 when not retained
+
+	// Trend metrics are nil when there isn't enough history to compute them
+	Slope90d             *float64 // Least-squares change in daily entries per day over 90 days
+	Slope365d            *float64 // Same over 365 days
+	YoYChangePct         *float64 // 30-day average vs. the same window a year earlier, in percent
+	RecoveryRatio        *float64 // 30-day average / same window in RecoveryBaselineYear
+	RecoveryBaselineYear int
+
+	ModelVersion string // Scoring model that produced GhostScore
+	WindowEnd    string // Date the rolling windows end on (YYYY-MM-DD)
+}
+
+// ScoreExplanation is the persisted breakdown of how a station's ghost score was derived
+type ScoreExplanation struct {
+	StationID     string
+	StationName   string
+	ModelVersion  string
+	GhostScore    int
+	Rank          int // 1 = ghostliest; 0 when unscored
+	PeerGroupSize int // stations scored against each other
+	WindowEnd     string
+	Window30Start string
+	Window90Start string
+	Inputs        string // JSON object of raw input values
+	Factors       string // JSON array of per-factor contributions
+	ComputedAt    string
+}
+
+// UpsertScoreExplanation replaces a station's latest score explanation
+func (c *Client) UpsertScoreExplanation(e ScoreExplanation) error {
+	_, err := c.db.Exec(`
+		INSERT INTO GhostScoreExplanation (
+			id, stationId, modelVersion, ghostScore, rank, peerGroupSize,
+			windowEnd, window30Start, window90Start, inputs, factors, computedAt
+		) VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(stationId) DO UPDATE SET
+		modelVersion = excluded.modelVersion,
+		ghostScore = excluded.ghostScore,
+		rank = excluded.rank,
+		peerGroupSize = excluded.peerGroupSize,
+		windowEnd = excluded.windowEnd,
+		window30Start = excluded.window30Start,
+		window90Start = excluded.window90Start,
+		inputs = excluded.inputs,
+		factors = excluded.factors,
+		computedAt = excluded.computedAt`,
+		e.StationID, e.ModelVersion, e.GhostScore, e.Rank, e.PeerGroupSize,
+		e.WindowEnd, e.Window30Start, e.Window90Start, e.Inputs, e.Factors,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert score explanation: %w", err)
+	}
+	return nil
+}
+
+// GetScoreExplanations returns the latest explanations for stations in a city whose
+// name matches stationName or whose alias matches its normalized form
+func (c *Client) GetScoreExplanations(cityCode, stationName, normalized string) ([]ScoreExplanation, error) {
+	rows, err := c.db.Query(`
+		SELECT DISTINCT
+			s.id, s.name, e.modelVersion, e.ghostScore, e.rank, e.peerGroupSize,
+			e.windowEnd, e.window30Start, e.window90Start, e.inputs, e.factors, e.computedAt
+		FROM GhostScoreExplanation e
+		JOIN Station s ON s.id = e.stationId
+		JOIN City c ON c.id = s.cityId
+		LEFT JOIN StationAlias sa ON sa.stationId = s.id
+		WHERE c.code = ?
+		AND (s.name = ? COLLATE NOCASE OR sa.normalized = ?)
+		ORDER BY s.name`,
+		cityCode, stationName, normalized,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query score explanations: %w", err)
+	}
+	defer rows.Close()
+
+	var explanations []ScoreExplanation
+	for rows.Next() {
+		var e ScoreExplanation
+		err := rows.Scan(
+			&e.StationID, &e.StationName, &e.ModelVersion, &e.GhostScore, &e.Rank, &e.PeerGroupSize,
+			&e.WindowEnd, &e.Window30Start, &e.Window90Start, &e.Inputs, &e.Factors, &e.ComputedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan score explanation: %w", err)
+		}
+		explanations = append(explanations, e)
+	}
+
+	return explanations, rows.Err()
+}
+
+// StationRef identifies a station by ID and display name
+type StationRef struct {
+	ID   string
+	Name string
+}
+
+// FindStations returns stations in a city whose name matches name or whose alias
+// matches its normalized form
+func (c *Client) FindStations(cityCode, name, normalized string) ([]StationRef, error) {
+	rows, err := c.db.Query(`
+		SELECT DISTINCT s.id, s.name
+		FROM Station s
+		JOIN City c ON c.id = s.cityId
+		LEFT JOIN StationAlias sa ON sa.stationId = s.id
+		WHERE c.code = ?
+		AND (s.name = ? COLLATE NOCASE OR sa.normalized = ?)
+		ORDER BY s.name`,
+		cityCode, name, normalized,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stations: %w", err)
+	}
+	defer rows.Close()
+
+	var stations []StationRef
+	for rows.Next() {
+		var s StationRef
+		if err := rows.Scan(&s.ID, &s.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan station: %w", err)
+		}
+		stations = append(stations, s)
+	}
+
+	return stations, rows.Err()
+}
+
+// MetricsSnapshot is a station's metrics as of one date
+type MetricsSnapshot struct {
+	StationID      string
+	SnapshotDate   string // Date the rolling windows ended on (YYYY-MM-DD)
+	GhostScore     int
+	Rolling30dAvg  float64
+	Rolling90dAvg  float64
+	LastDayEntries int
+	DataStatus     string
+	ModelVersion   string
+	ComputedAt     string
+}
+
+// InsertMetricsSnapshot records a station's metrics for its window end date.
+// Re-running compute for the same date replaces that date's snapshot.
+func (c *Client) InsertMetricsSnapshot(m StationMetric) error {
+	if m.WindowEnd == "" {
+		return fmt.Errorf("cannot snapshot metrics for station %s without a window end date", m.StationID)
+	}
+
+	_, err := c.db.Exec(`
+		INSERT INTO StationMetricsHistory (
+			id, stationId, snapshotDate, ghostScore, rolling30dAvg, rolling90dAvg,
+			lastDayEntries, dataStatus, modelVersion, computedAt
+		) VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(stationId, snapshotDate) DO UPDATE SET
+		ghostScore = excluded.ghostScore,
+		rolling30dAvg = excluded.rolling30dAvg,
+		rolling90dAvg = excluded.rolling90dAvg,
+		lastDayEntries = excluded.lastDayEntries,
+		dataStatus = excluded.dataStatus,
+		modelVersion = excluded.modelVersion,
+		computedAt = excluded.computedAt`,
+		m.StationID, m.WindowEnd, m.GhostScore, m.Rolling30dAvg, m.Rolling90dAvg,
+		m.LastDayEntries, m.DataStatus, m.ModelVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetMetricsHistory returns a station's snapshots between from and to (inclusive, YYYY-MM-DD).
+// Empty bounds are open-ended.
+func (c *Client) GetMetricsHistory(stationID, from, to string) ([]MetricsSnapshot, error) {
+	rows, err := c.db.Query(`
+		SELECT stationId, snapshotDate, ghostScore, rolling30dAvg, rolling90dAvg,
+			lastDayEntries, dataStatus, modelVersion, computedAt
+		FROM StationMetricsHistory
+		WHERE stationId = ?
+		AND (? = '' OR snapshotDate >= ?)
+		AND (? = '' OR snapshotDate <= ?)
+		ORDER BY snapshotDate ASC`,
+		stationID, from, from, to, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics history: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []MetricsSnapshot
+	for rows.Next() {
+		var s MetricsSnapshot
+		err := rows.Scan(
+			&s.StationID, &s.SnapshotDate, &s.GhostScore, &s.Rolling30dAvg, &s.Rolling90dAvg,
+			&s.LastDayEntries, &s.DataStatus, &s.ModelVersion, &s.ComputedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan metrics snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// HistoryRetention controls how long metrics snapshots are kept
+type HistoryRetention struct {
+	DailyDays int // Keep every snapshot this many days back; older ones are thinned to one per week
+	MaxDays   int // Delete snapshots older than this; 0 keeps them forever
+}
+
+// DefaultHistoryRetention keeps 90 days of daily snapshots and weekly ones indefinitely
+var DefaultHistoryRetention = HistoryRetention{DailyDays: 90}
+
+// PruneMetricsHistory applies a retention policy relative to the city's latest snapshot date.
+// It returns the number of snapshots deleted.
+func (c *Client) PruneMetricsHistory(cityCode string, r HistoryRetention) (int64, error) {
+	var latest sql.NullString
+	err := c.db.QueryRow(`
+		SELECT MAX(h.snapshotDate)
+		FROM StationMetricsHistory h
+		JOIN Station s ON s.id = h.stationId
+		JOIN City c ON c.id = s.cityId
+		WHERE c.code = ?`,
+		cityCode,
+	).Scan(&latest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest snapshot date: %w", err)
+	}
+	if !latest.Valid {
+		return 0, nil // No history yet
+	}
+
+	var deleted int64
+
+	if r.DailyDays > 0 {
+		// Keep the latest snapshot of each week (Monday to Sunday, including weeks
+		// spanning New Year) for each station
+		result, err := c.db.Exec(`
+			DELETE FROM StationMetricsHistory
+			WHERE snapshotDate < date(?, printf('-%d day', ?))
+			AND stationId IN (
+				SELECT s.id
+				FROM Station s
+				JOIN City c ON c.id = s.cityId
+				WHERE c.code = ?
+			)
+			AND id NOT IN (
+				SELECT h.id
+				FROM StationMetricsHistory h
+				WHERE h.snapshotDate = (
+					SELECT MAX(h2.snapshotDate)
+					FROM StationMetricsHistory h2
+					WHERE h2.stationId = h.stationId
+					AND date(h2.snapshotDate, 'weekday 0', '-6 days') = date(h.snapshotDate, 'weekday 0', '-6 days')
+				)
+			)`,
+			latest.String, r.DailyDays, cityCode,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to thin metrics history: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+
+	if r.MaxDays > 0 {
+		result, err := c.db.Exec(`
+			DELETE FROM StationMetricsHistory
+			WHERE snapshotDate < date(?, printf('-%d day', ?))
+			AND stationId IN (
+				SELECT s.id
+				FROM Station s
+				JOIN City c ON c.id = s.cityId
+				WHERE c.code = ?
+			)`,
+			latest.String, r.MaxDays, cityCode,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune metrics history: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+
+	return deleted, nil
+}
+
+// DailyEntry is one day of ridership for a station
+type DailyEntry struct {
+	ServiceDate string // YYYY-MM-DD
+	Entries     int
+}
+
+// GetDailyRidershipSeries returns each station's ridership from a date onward, oldest first
+func (c *Client) GetDailyRidershipSeries(cityCode, from string) (map[string][]DailyEntry, error) {
+	rows, err := c.db.Query(`
+		SELECT rd.stationId, date(rd.serviceDate), rd.entries
+		FROM RidershipDaily rd
+		JOIN Station s ON s.id = rd.stationId
+		JOIN City c ON c.id = s.cityId
+		WHERE c.code = ?
+		AND date(rd.serviceDate) >= ?
+		ORDER BY rd.stationId, date(rd.serviceDate)`,
+		cityCode, from,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ridership series: %w", err)
+	}
+	defer rows.Close()
+
+	series := make(map[string][]DailyEntry)
+	for rows.Next() {
+		var stationID string
+		var e DailyEntry
+		if err := rows.Scan(&stationID, &e.ServiceDate, &e.Entries); err != nil {
+			return nil, fmt.Errorf("failed to scan ridership row: %w", err)
+		}
+		series[stationID] = append(series[stationID], e)
+	}
+
+	return series, rows.Err()
+}
+
+// RidershipAnomaly is a day whose ridership departs from the station's seasonal baseline
+type RidershipAnomaly struct {
+	StationID   string
+	StationName string
+	ServiceDate string // YYYY-MM-DD
+	Kind        string // "drop" or "spike"
+	Severity    string // "low", "medium" or "high"
+	Entries     int
+	Expected    float64
+	Score       float64 // Robust z-score
+	DetectedAt  string
+}
+
+// UpsertRidershipAnomaly records an anomaly, replacing any earlier result for the same day
+func (c *Client) UpsertRidershipAnomaly(a RidershipAnomaly) error {
+	_, err := c.db.Exec(`
+		INSERT INTO RidershipAnomaly (
+			id, stationId, serviceDate, kind, severity, entries, expected, score, detectedAt
+		) VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(stationId, serviceDate) DO UPDATE SET
+		kind = excluded.kind,
+		severity = excluded.severity,
+		entries = excluded.entries,
+		expected = excluded.expected,
+		score = excluded.score,
+		detectedAt = excluded.detectedAt`,
+		a.StationID, a.ServiceDate, a.Kind, a.Severity, a.Entries, a.Expected, a.Score,
+	)
+	return err
+}
+
+// ClearRidershipAnomalies removes a station's anomalies from a date onward so
+// re-detection over revised data doesn't leave stale results
+func (c *Client) ClearRidershipAnomalies(stationID, from string) error {
+	_, err := c.db.Exec(`
+		DELETE FROM RidershipAnomaly
+		WHERE stationId = ? AND serviceDate >= ?`,
+		stationID, from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear anomalies: %w", err)
+	}
+	return nil
+}
+
+// GetRidershipAnomalies returns a city's anomalies on or after since, newest first
+func (c *Client) GetRidershipAnomalies(cityCode, since string) ([]RidershipAnomaly, error) {
+	rows, err := c.db.Query(`
+		SELECT a.stationId, s.name, a.serviceDate, a.kind, a.severity,
+			a.entries, a.expected, a.score, a.detectedAt
+		FROM RidershipAnomaly a
+		JOIN Station s ON s.id = a.stationId
+		JOIN City c ON c.id = s.cityId
+		WHERE c.code = ?
+		AND a.serviceDate >= ?
+		ORDER BY a.serviceDate DESC, ABS(a.score) DESC`,
+		cityCode, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	var anomalies []RidershipAnomaly
+	for rows.Next() {
+		var a RidershipAnomaly
+		err := rows.Scan(
+			&a.StationID, &a.StationName, &a.ServiceDate, &a.Kind, &a.Severity,
+			&a.Entries, &a.Expected, &a.Score, &a.DetectedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan anomaly: %w", err)
+		}
+		anomalies = append(anomalies, a)
+	}
+
+	return anomalies, rows.Err()
+}
+
+// GetAverageEntriesBetween returns each station's average daily entries between two dates (inclusive)
+func (c *Client) GetAverageEntriesBetween(cityCode, from, to string) (map[string]float64, error) {
+	rows, err := c.db.Query(`
+		SELECT rd.stationId, AVG(rd.entries)
+		FROM RidershipDaily rd
+		JOIN Station s ON s.id = rd.stationId
+		JOIN City c ON c.id = s.cityId
+		WHERE c.code = ?
+		AND date(rd.serviceDate) BETWEEN ? AND ?
+		GROUP BY rd.stationId`,
+		cityCode, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query average entries: %w", err)
+	}
+	defer rows.Close()
+
+	averages := make(map[string]float64)
+	for rows.Next() {
+		var stationID string
+		var avg float64
+		if err := rows.Scan(&stationID, &avg); err != nil {
+			return nil, fmt.Errorf("failed to scan average entries: %w", err)
+		}
+		averages[stationID] = avg
+	}
+
+	return averages, rows.Err()
+}
+
+// Day types used by CTA ridership data
+const (
+	DayTypeWeekday       = "W"
+	DayTypeSaturday      = "A"
+	DayTypeSundayHoliday = "U"
+)
+
+// DayTypes lists the day types in display order
+var DayTypes = []string{DayTypeWeekday, DayTypeSaturday, DayTypeSundayHoliday}
+
+// DayTypeName returns a human-readable label for a day type code
+func DayTypeName(dayType string) string {
+	switch dayType {
+	case DayTypeWeekday:
+		return "Weekday"
+	case DayTypeSaturday:
+		return "Saturday"
+	case DayTypeSundayHoliday:
+		return "Sunday/Holiday"
+	}
+	return dayType
+}
+
+// dayTypeExpr is a row's day type, derived from the calendar for rows loaded
+// without one (holidays then count as weekdays)
+const dayTypeExpr = `COALESCE(rd.dayType, CASE strftime('%w', rd.serviceDate)
+	WHEN '6' THEN 'A' WHEN '0' THEN 'U' ELSE 'W' END)`
+
+// DayTypeMetric is a station's ridership and ghost score on one day type
+type DayTypeMetric struct {
+	StationID      string
+	Name           string
+	DayType        string
+	Rolling30dAvg  float64
+	Rolling90dAvg  float64
+	Days90d        int     // Days of this type with ridership in the 90-day window
+	ScheduledTrips float64 // Scheduled trips on this day type; 0 without GTFS calendar data
+	RidersPerTrain float64 // Rolling30dAvg / ScheduledTrips
+	Status         string  // Lifecycle status on WindowEnd
+	GhostScore     int
+	ModelVersion   string
+	WindowEnd      string
+}
+
+// GetDayTypeMetrics retrieves each station's rolling averages split by day type,
+// with windows ending on windowEnd (YYYY-MM-DD), for the stations not yet
+// deactivated on asOf (zero for today). Every station gets a row per day type;
+// Days90d is 0 when it had no ridership on that day type.
+func (c *Client) GetDayTypeMetrics(cityCode, windowEnd string, asOf time.Time) ([]DayTypeMetric, error) {
+	var asOfArg interface{}
+	if !asOf.IsZero() {
+		asOfArg = asOf.Format("2006-01-02")
+	}
+
+	query := `
+		WITH DayTypes(dayType) AS (
+			VALUES ('W'), ('A'), ('U')
+		),
+		Daily AS (
+			SELECT rd.stationId, rd.serviceDate, rd.entries, ` + dayTypeExpr + ` as dayType
+			FROM RidershipDaily rd
+			JOIN Station s ON s.id = rd.stationId
+			JOIN City c ON c.id = s.cityId
+			WHERE c.code = ?
+			AND date(rd.serviceDate) <= date(?)
+			AND date(rd.serviceDate) >= date(?, '-90 days')
+		)
+		SELECT
+			s.id,
+			s.name,
+			dt.dayType,
+			COALESCE(AVG(CASE
+				WHEN d.serviceDate >= date(?, '-30 days') THEN d.entries
+			END), 0) as rolling30dAvg,
+			COALESCE(AVG(d.entries), 0) as rolling90dAvg,
+			COUNT(d.entries) as days90d,
+			COALESCE(CASE dt.dayType
+				WHEN 'W' THEN ss.weekdayTrips
+				WHEN 'A' THEN ss.saturdayTrips
+				ELSE ss.sundayTrips
+			END, 0) as scheduledTrips,
+			` + stationStatusAt("?") + ` as status
+		FROM Station s
+		JOIN City c ON c.id = s.cityId
+		CROSS JOIN DayTypes dt
+		LEFT JOIN Daily d ON d.stationId = s.id AND d.dayType = dt.dayType
+		LEFT JOIN StationService ss ON ss.stationId = s.id
+		WHERE c.code = ? AND ` + stationInServiceOn("COALESCE(?, 'now')") + `
+		GROUP BY s.id, s.name, dt.dayType`
+
+	rows, err := c.db.Query(query, cityCode, windowEnd, windowEnd, windowEnd, windowEnd, windowEnd, cityCode, asOfArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query day type metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []DayTypeMetric
+	for rows.Next() {
+		m := DayTypeMetric{WindowEnd: windowEnd}
+		if err := rows.Scan(&m.StationID, &m.Name, &m.DayType, &m.Rolling30dAvg,
+			&m.Rolling90dAvg, &m.Days90d, &m.ScheduledTrips, &m.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan day type metric: %w", err)
+		}
+		if m.ScheduledTrips > 0 {
+			m.RidersPerTrain = m.Rolling30dAvg / m.ScheduledTrips
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
+
+// UpsertDayTypeMetric replaces a station's metrics for one day type
+func (c *Client) UpsertDayTypeMetric(m DayTypeMetric) error {
+	var trips, perTrain interface{}
+	if m.ScheduledTrips > 0 {
+		trips, perTrain = m.ScheduledTrips, m.RidersPerTrain
+	}
+
+	_, err := c.db.Exec(`
+		INSERT INTO StationDayTypeMetrics (
+			id, stationId, dayType, rolling30dAvg, rolling90dAvg, days90d,
+			scheduledTrips, ridersPerTrain, ghostScore, modelVersion, windowEnd, lastUpdated
+		) VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(stationId, dayType) DO UPDATE SET
+			rolling30dAvg = excluded.rolling30dAvg,
+			rolling90dAvg = excluded.rolling90dAvg,
+			days90d = excluded.days90d,
+			scheduledTrips = excluded.scheduledTrips,
+			ridersPerTrain = excluded.ridersPerTrain,
+			ghostScore = excluded.ghostScore,
+			modelVersion = excluded.modelVersion,
+			windowEnd = excluded.windowEnd,
+			lastUpdated = excluded.lastUpdated`,
+		m.StationID, m.DayType, m.Rolling30dAvg, m.Rolling90dAvg, m.Days90d,
+		trips, perTrain, m.GhostScore, nullIfEmpty(m.ModelVersion), m.WindowEnd,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert day type metrics: %w", err)
+	}
+	return nil
+}
+
+// GetStationDayTypeMetrics retrieves a station's stored metrics for each day type
+func (c *Client) GetStationDayTypeMetrics(stationID string) ([]DayTypeMetric, error) {
+	rows, err := c.db.Query(`
+		SELECT dm.stationId, s.name, dm.dayType, dm.rolling30dAvg, dm.rolling90dAvg,
+			dm.days90d, COALESCE(dm.scheduledTrips, 0), COALESCE(dm.ridersPerTrain, 0),
+			dm.ghostScore, COALESCE(dm.modelVersion, ''), dm.windowEnd
+		FROM StationDayTypeMetrics dm
+		JOIN Station s ON s.id = dm.stationId
+		WHERE dm.stationId = ?
+		ORDER BY CASE dm.dayType WHEN 'W' THEN 0 WHEN 'A' THEN 1 ELSE 2 END`,
+		stationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query day type metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []DayTypeMetric
+	for rows.Next() {
+		var m DayTypeMetric
+		if err := rows.Scan(&m.StationID, &m.Name, &m.DayType, &m.Rolling30dAvg, &m.Rolling90dAvg,
+			&m.Days90d, &m.ScheduledTrips, &m.RidersPerTrain, &m.GhostScore, &m.ModelVersion,
+			&m.WindowEnd); err != nil {
+			return nil, fmt.Errorf("failed to scan day type metric: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
+
+// SyncCheckpoint records how far an interrupted paginated sync got
+type SyncCheckpoint struct {
+	Dataset      string
+	Since        string // YYYY-MM-DD
+	Offset       int
+	LastDate     string
+	UpdatedSince string // Revisions after this :updated_at are re-fetched; empty for none
+	MaxUpdatedAt string // Latest :updated_at seen so far
+	Progress     string // Caller's JSON summary of the pages already done, restored on resume
+}
+
+// GetSyncCheckpoint returns the saved checkpoint for a city's dataset, or nil if none
+func (c *Client) GetSyncCheckpoint(cityID, dataset string) (*SyncCheckpoint, error) {
+	cp := SyncCheckpoint{Dataset: dataset}
+	var lastDate, updatedSince, maxUpdatedAt, progress sql.NullString
+	err := c.db.QueryRow(`
+		SELECT since, rowOffset, lastDate, updatedSince, maxUpdatedAt, progress
+		FROM SyncCheckpoint
+		WHERE cityId = ? AND dataset = ?`,
+		cityID, dataset,
+	).Scan(&cp.Since, &cp.Offset, &lastDate, &updatedSince, &maxUpdatedAt, &progress)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync checkpoint: %w", err)
+	}
+	cp.LastDate = lastDate.String
+	cp.UpdatedSince = updatedSince.String
+	cp.MaxUpdatedAt = maxUpdatedAt.String
+	cp.Progress = progress.String
+	return &cp, nil
+}
+
+// SaveSyncCheckpoint creates or advances a city's checkpoint for a dataset
+func (c *Client) SaveSyncCheckpoint(cityID string, cp SyncCheckpoint) error {
+	_, err := c.db.Exec(`
+		INSERT INTO SyncCheckpoint (
+			id, cityId, dataset, since, rowOffset, lastDate, updatedSince, maxUpdatedAt, progress, updatedAt
+		) VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(cityId, dataset) DO UPDATE SET
+			since = excluded.since,
+			rowOffset = excluded.rowOffset,
+			lastDate = excluded.lastDate,
+			updatedSince = excluded.updatedSince,
+			maxUpdatedAt = excluded.maxUpdatedAt,
+			progress = excluded.progress,
+			updatedAt = excluded.updatedAt`,
+		cityID, cp.Dataset, cp.Since, cp.Offset, nullIfEmpty(cp.LastDate),
+		nullIfEmpty(cp.UpdatedSince), nullIfEmpty(cp.MaxUpdatedAt), nullIfEmpty(cp.Progress),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sync checkpoint: %w", err)
+	}
+	return nil
+}
+
+// DeleteSyncCheckpoint clears a city's checkpoint once its sync has completed
+func (c *Client) DeleteSyncCheckpoint(cityID, dataset string) error {
+	_, err := c.db.Exec("DELETE FROM SyncCheckpoint WHERE cityId = ? AND dataset = ?", cityID, dataset)
+	if err != nil {
+		return fmt.Errorf("failed to delete sync checkpoint: %w", err)
+	}
+	return nil
+}
+
+// GetSyncWatermark returns the latest :updated_at seen by a completed sync of a
+// city's dataset, or "" before the first one
+func (c *Client) GetSyncWatermark(cityID, dataset string) (string, error) {
+	var updatedAt string
+	err := c.db.QueryRow(`
+		SELECT updatedAt FROM SyncWatermark WHERE cityId = ? AND dataset = ?`,
+		cityID, dataset,
+	).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query sync watermark: %w", err)
+	}
+	return updatedAt, nil
+}
+
+// SaveSyncWatermark records the latest :updated_at seen by a completed sync
+func (c *Client) SaveSyncWatermark(cityID, dataset, updatedAt string) error {
+	_, err := c.db.Exec(`
+		INSERT INTO SyncWatermark (id, cityId, dataset, updatedAt, syncedAt)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, datetime('now'))
+		ON CONFLICT(cityId, dataset) DO UPDATE SET
+			updatedAt = excluded.updatedAt,
+			syncedAt = excluded.syncedAt`,
+		cityID, dataset, updatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sync watermark: %w", err)
+	}
+	return nil
+}
+
+// Station lifecycle statuses
+const (
+	StationOpen              = "open"
+	StationTemporarilyClosed = "temporarily_closed"
+	StationPermanentlyClosed = "permanently_closed"
+)
+
+// StationStatuses lists the lifecycle statuses a station can be given
+var StationStatuses = []string{StationOpen, StationTemporarilyClosed, StationPermanentlyClosed}
+
+// StationStatusName returns a human-readable label for a lifecycle status
+func StationStatusName(status string) string {
+	switch status {
+	case StationOpen:
+		return "open"
+	case StationTemporarilyClosed:
+		return "temporarily closed"
+	case StationPermanentlyClosed:
+		return "permanently closed"
+	}
+	return status
+}
+
+// stationStatusAt is the SQL for station s's lifecycle status on dateExpr: the
+// latest-starting record in effect that day, or open when there is none
+func stationStatusAt(dateExpr string) string {
+	return `COALESCE((
+		SELECT st.status FROM StationStatus st
+		WHERE st.stationId = s.id
+		AND st.effectiveFrom <= date(` + dateExpr + `)
+		AND (st.effectiveTo IS NULL OR st.effectiveTo >= date(` + dateExpr + `))
+		ORDER BY st.effectiveFrom DESC
+		LIMIT 1
+	), 'open')`
+}
+
+// stationInServiceOn is the SQL for whether station s was still in the feed on
+// dateExpr: active now, or deactivated after that day. Past dates then keep the
+// stations open at the time.
+func stationInServiceOn(dateExpr string) string {
+	return `(s.active = 1 OR date(s.deactivatedAt) > date(` + dateExpr + `))`
+}
+
+// StationStatus is one period of a station's lifecycle
+type StationStatus struct {
+	StationID     string
+	StationName   string
+	Status        string // One of StationStatuses
+	EffectiveFrom string // YYYY-MM-DD
+	EffectiveTo   string // Last day in effect (YYYY-MM-DD); "" while open-ended
+	Note          string
+}
+
+// SetStationStatus records a status taking effect on EffectiveFrom, replacing any
+// record for the station that starts the same day
+func (c *Client) SetStationStatus(s StationStatus) error {
+	_, err := c.db.Exec(`
+		INSERT INTO StationStatus (id, stationId, status, effectiveFrom, effectiveTo, note, createdAt)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(stationId, effectiveFrom) DO UPDATE SET
+			status = excluded.status,
+			effectiveTo = excluded.effectiveTo,
+			note = excluded.note`,
+		s.StationID, s.Status, s.EffectiveFrom, nullIfEmpty(s.EffectiveTo), nullIfEmpty(s.Note),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set station status: %w", err)
+	}
+	return nil
+}
+
+// GetStationStatuses lists status records for a city, oldest first. An empty
+// stationID lists every station's records.
+func (c *Client) GetStationStatuses(cityCode, stationID string) ([]StationStatus, error) {
+	rows, err := c.db.Query(`
+		SELECT st.stationId, s.name, st.status, st.effectiveFrom,
+			COALESCE(st.effectiveTo, ''), COALESCE(st.note, '')
+		FROM StationStatus st
+		JOIN Station s ON s.id = st.stationId
+		JOIN City c ON c.id = s.cityId
+		WHERE c.code = ? AND (? = '' OR st.stationId = ?)
+		ORDER BY s.name, st.effectiveFrom`,
+		cityCode, stationID, stationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query station statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []StationStatus
+	for rows.Next() {
+		var s StationStatus
+		if err := rows.Scan(&s.StationID, &s.StationName, &s.Status, &s.EffectiveFrom,
+			&s.EffectiveTo, &s.Note); err != nil {
+			return nil, fmt.Errorf("failed to scan station status: %w", err)
+		}
+		statuses = append(statuses, s)
+	}
+
+	return statuses, rows.Err()
+}
+
+// DeleteStationStatus removes the station's record starting on effectiveFrom and
+// reports whether there was one
+func (c *Client) DeleteStationStatus(stationID, effectiveFrom string) (bool, error) {
+	result, err := c.db.Exec(`
+		DELETE FROM StationStatus WHERE stationId = ? AND effectiveFrom = ?`,
+		stationID, effectiveFrom,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete station status: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete station status: %w", err)
+	}
+	return n > 0, nil
+}
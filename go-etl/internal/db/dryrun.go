@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DryRun is a scratch copy of a database. Commands write to the copy instead of
+// the real database so their effects can be reported and then discarded.
+type DryRun struct {
+	*Client
+	sourcePath string
+	copyPath   string
+}
+
+// OpenDryRun copies the database at databaseURL to a temporary file and connects to the copy
+func OpenDryRun(databaseURL string) (*DryRun, error) {
+	sourcePath := strings.TrimPrefix(databaseURL, "file:")
+
+	source, err := NewClient(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	tmp, err := os.CreateTemp("", "ghost-stops-dry-run-*.db")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dry-run database: %w", err)
+	}
+	copyPath := tmp.Name()
+	tmp.Close()
+	os.Remove(copyPath) // VACUUM INTO needs a path that doesn't exist yet
+
+	if _, err := source.db.Exec("VACUUM INTO ?", copyPath); err != nil {
+		os.Remove(copyPath)
+		return nil, fmt.Errorf("failed to copy database for dry run: %w", err)
+	}
+
+	client, err := NewClient(copyPath)
+	if err != nil {
+		os.Remove(copyPath)
+		return nil, err
+	}
+
+	return &DryRun{Client: client, sourcePath: sourcePath, copyPath: copyPath}, nil
+}
+
+// Close disconnects from the copy and deletes it
+func (d *DryRun) Close() error {
+	err := d.Client.Close()
+	for _, suffix := range []string{"", "-journal", "-wal", "-shm"} {
+		os.Remove(d.copyPath + suffix)
+	}
+	return err
+}
+
+// TableDiff counts the rows a dry run changed in one table, matched by id
+type TableDiff struct {
+	Table    string
+	Inserted int
+	Updated  int
+	Deleted  int
+}
+
+// ScoreChange is a station whose ghost score would change
+type ScoreChange struct {
+	StationName string
+	Before      int // -1 when unscored, including stations with no previous metrics
+	After       int
+}
+
+// DryRunDiff summarizes what a dry run would have changed
+type DryRunDiff struct {
+	Tables []TableDiff
+
+	// Service date ranges (YYYY-MM-DD) of ridership rows added and pruned
+	RidershipAddedFrom, RidershipAddedTo   string
+	RidershipPrunedFrom, RidershipPrunedTo string
+
+	ScoreChanges []ScoreChange // Largest changes first
+}
+
+// Diff compares the copy against the original database
+func (d *DryRun) Diff() (*DryRunDiff, error) {
+	ctx := context.Background()
+
+	// ATTACH only applies to one connection, so run everything on the same one
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS orig", d.sourcePath); err != nil {
+		return nil, fmt.Errorf("failed to attach original database: %w", err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE orig")
+
+	tables, err := diffTables(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	diff := &DryRunDiff{Tables: tables}
+
+	var addedFrom, addedTo, prunedFrom, prunedTo sql.NullString
+	err = conn.QueryRowContext(ctx, `
+		SELECT
+			(SELECT MIN(date(serviceDate)) FROM main.RidershipDaily WHERE id NOT IN (SELECT id FROM orig.RidershipDaily)),
+			(SELECT MAX(date(serviceDate)) FROM main.RidershipDaily WHERE id NOT IN (SELECT id FROM orig.RidershipDaily)),
+			(SELECT MIN(date(serviceDate)) FROM orig.RidershipDaily WHERE id NOT IN (SELECT id FROM main.RidershipDaily)),
+			(SELECT MAX(date(serviceDate)) FROM orig.RidershipDaily WHERE id NOT IN (SELECT id FROM main.RidershipDaily))`,
+	).Scan(&addedFrom, &addedTo, &prunedFrom, &prunedTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare ridership: %w", err)
+	}
+	diff.RidershipAddedFrom, diff.RidershipAddedTo = addedFrom.String, addedTo.String
+	diff.RidershipPrunedFrom, diff.RidershipPrunedTo = prunedFrom.String, prunedTo.String
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT s.name, COALESCE(o.ghostScore, -1), m.ghostScore
+		FROM main.StationMetrics m
+		JOIN main.Station s ON s.id = m.stationId
+		LEFT JOIN orig.StationMetrics o ON o.stationId = m.stationId
+		WHERE o.ghostScore IS NULL OR o.ghostScore != m.ghostScore`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare ghost scores: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ScoreChange
+		if err := rows.Scan(&c.StationName, &c.Before, &c.After); err != nil {
+			return nil, fmt.Errorf("failed to scan score change: %w", err)
+		}
+		diff.ScoreChanges = append(diff.ScoreChanges, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(diff.ScoreChanges, func(i, j int) bool {
+		return math.Abs(float64(diff.ScoreChanges[i].After-diff.ScoreChanges[i].Before)) >
+			math.Abs(float64(diff.ScoreChanges[j].After-diff.ScoreChanges[j].Before))
+	})
+
+	return diff, nil
+}
+
+// diffTables counts inserted, updated and deleted rows in every table with an id column
+func diffTables(ctx context.Context, conn *sql.Conn) ([]TableDiff, error) {
+	rows, err := conn.QueryContext(ctx, `
+		SELECT m.name
+		FROM main.sqlite_master m
+		JOIN orig.sqlite_master o ON o.name = m.name AND o.type = 'table'
+		WHERE m.type = 'table'
+		AND m.name NOT LIKE 'sqlite_%'
+		AND m.name NOT LIKE '_prisma_%'
+		AND EXISTS (SELECT 1 FROM pragma_table_info(m.name) WHERE name = 'id')
+		ORDER BY m.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	var diffs []TableDiff
+	for _, table := range tables {
+		t := TableDiff{Table: table}
+		query := fmt.Sprintf(`
+			SELECT
+				(SELECT COUNT(*) FROM main."%[1]s" WHERE id NOT IN (SELECT id FROM orig."%[1]s")),
+				(SELECT COUNT(*) FROM (
+					SELECT * FROM main."%[1]s" WHERE id IN (SELECT id FROM orig."%[1]s")
+					EXCEPT SELECT * FROM orig."%[1]s"
+				)),
+				(SELECT COUNT(*) FROM orig."%[1]s" WHERE id NOT IN (SELECT id FROM main."%[1]s"))`, table)
+		if err := conn.QueryRowContext(ctx, query).Scan(&t.Inserted, &t.Updated, &t.Deleted); err != nil {
+			return nil, fmt.Errorf("failed to compare %s: %w", table, err)
+		}
+		if t.Inserted+t.Updated+t.Deleted > 0 {
+			diffs = append(diffs, t)
+		}
+	}
+
+	return diffs, nil
+}
+
+// Print writes the diff as a report, listing at most maxScores score changes
+func (diff *DryRunDiff) Print(w io.Writer, maxScores int) {
+	fmt.Fprintln(w, "\n--- Dry Run: changes that were NOT saved ---")
+	if len(diff.Tables) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	fmt.Fprintf(w, "%-25s %10s %10s %10s\n", "Table", "Inserted", "Updated", "Deleted")
+	for _, t := range diff.Tables {
+		fmt.Fprintf(w, "%-25s %10d %10d %10d\n", t.Table, t.Inserted, t.Updated, t.Deleted)
+	}
+
+	if diff.RidershipAddedFrom != "" {
+		fmt.Fprintf(w, "\nRidership added: %s to %s\n", diff.RidershipAddedFrom, diff.RidershipAddedTo)
+	}
+	if diff.RidershipPrunedFrom != "" {
+		fmt.Fprintf(w, "Ridership pruned: %s to %s\n", diff.RidershipPrunedFrom, diff.RidershipPrunedTo)
+	}
+
+	if len(diff.ScoreChanges) > 0 {
+		fmt.Fprintf(w, "\nGhost score changes: %d stations\n", len(diff.ScoreChanges))
+		for i, c := range diff.ScoreChanges {
+			if i == maxScores {
+				fmt.Fprintf(w, "... and %d more\n", len(diff.ScoreChanges)-maxScores)
+				break
+			}
+			fmt.Fprintf(w, "  %-35s %4s -> %-4s\n", c.StationName, scoreLabel(c.Before), scoreLabel(c.After))
+		}
+	}
+}
+
+func scoreLabel(score int) string {
+	if score < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d", score)
+}
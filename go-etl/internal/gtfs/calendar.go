@@ -0,0 +1,117 @@
+package gtfs
+
+import (
+	"fmt"
+	"time"
+)
+
+const gtfsDateLayout = "20060102"
+
+// Calendar answers which service_ids run on a given date, combining
+// calendar.txt weekly patterns with calendar_dates.txt exceptions.
+type Calendar struct {
+	Start time.Time
+	End   time.Time
+
+	weekly     map[string]weeklyService
+	exceptions map[string]map[string]int // yyyymmdd -> service_id -> exception_type
+}
+
+type weeklyService struct {
+	days  [7]bool // indexed by time.Weekday
+	start time.Time
+	end   time.Time
+}
+
+// Calendar reads calendar.txt and calendar_dates.txt; either may be absent but not both
+func (f *Feed) Calendar() (*Calendar, error) {
+	cal := &Calendar{
+		weekly:     make(map[string]weeklyService),
+		exceptions: make(map[string]map[string]int),
+	}
+
+	if !f.HasFile("calendar.txt") && !f.HasFile("calendar_dates.txt") {
+		return nil, fmt.Errorf("feed has neither calendar.txt nor calendar_dates.txt")
+	}
+
+	if f.HasFile("calendar.txt") {
+		dayCols := [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+		required := append([]string{"service_id", "start_date", "end_date"}, dayCols[:]...)
+		err := f.readCSV("calendar.txt", required, func(r record) error {
+			start, err := time.Parse(gtfsDateLayout, r.get("start_date"))
+			if err != nil {
+				return fmt.Errorf("invalid start_date for service %s: %w", r.get("service_id"), err)
+			}
+			end, err := time.Parse(gtfsDateLayout, r.get("end_date"))
+			if err != nil {
+				return fmt.Errorf("invalid end_date for service %s: %w", r.get("service_id"), err)
+			}
+
+			svc := weeklyService{start: start, end: end}
+			for i, col := range dayCols {
+				svc.days[i] = r.get(col) == "1"
+			}
+			cal.weekly[r.get("service_id")] = svc
+			cal.extend(start)
+			cal.extend(end)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if f.HasFile("calendar_dates.txt") {
+		err := f.readCSV("calendar_dates.txt", []string{"service_id", "date", "exception_type"}, func(r record) error {
+			date, err := time.Parse(gtfsDateLayout, r.get("date"))
+			if err != nil {
+				return fmt.Errorf("invalid calendar_dates date for service %s: %w", r.get("service_id"), err)
+			}
+			key := date.Format(gtfsDateLayout)
+			if cal.exceptions[key] == nil {
+				cal.exceptions[key] = make(map[string]int)
+			}
+			cal.exceptions[key][r.get("service_id")] = r.getInt("exception_type")
+			cal.extend(date)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cal, nil
+}
+
+func (c *Calendar) extend(d time.Time) {
+	if c.Start.IsZero() || d.Before(c.Start) {
+		c.Start = d
+	}
+	if c.End.IsZero() || d.After(c.End) {
+		c.End = d
+	}
+}
+
+// ServicesOn returns the set of service_ids active on a date
+func (c *Calendar) ServicesOn(date time.Time) map[string]bool {
+	active := make(map[string]bool)
+	for id, svc := range c.weekly {
+		if date.Before(svc.start) || date.After(svc.end) {
+			continue
+		}
+		if svc.days[date.Weekday()] {
+			active[id] = true
+		}
+	}
+
+	for id, exceptionType := range c.exceptions[date.Format(gtfsDateLayout)] {
+		switch exceptionType {
+		case 1: // service added
+			active[id] = true
+		case 2: // service removed
+			delete(active, id)
+		}
+	}
+
+	return active
+}
@@ -0,0 +1,180 @@
+package gtfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+// DefaultRelocateMeters is how far a station must move between feeds to be reported
+const DefaultRelocateMeters = 100
+
+// Kinds of station change between feed versions
+const (
+	ChangeAdded       = "added"
+	ChangeRemoved     = "removed"
+	ChangeReactivated = "reactivated"
+	ChangeRenamed     = "renamed"
+	ChangeRelocated   = "relocated"
+	ChangeLines       = "lines"
+)
+
+// StationChange is one difference between the stored stations and a new feed
+type StationChange struct {
+	Kind       string
+	StationID  string // Station.id; empty for added stations
+	ExternalID string
+	Name       string
+	Detail     string
+}
+
+// DiffStations compares a feed's stations with the stored ones, matched by GTFS
+// stop ID. Moves shorter than relocateMeters are ignored.
+func DiffStations(stored []db.StoredStation, stations []Station, relocateMeters float64) []StationChange {
+	byExternalID := make(map[string]db.StoredStation, len(stored))
+	for _, s := range stored {
+		byExternalID[s.ExternalID] = s
+	}
+
+	var changes []StationChange
+	inFeed := make(map[string]bool, len(stations))
+	for _, station := range stations {
+		inFeed[station.ID] = true
+
+		old, ok := byExternalID[station.ID]
+		if !ok {
+			changes = append(changes, StationChange{
+				Kind:       ChangeAdded,
+				ExternalID: station.ID,
+				Name:       station.Name,
+				Detail:     strings.Join(station.LineNames(), ", "),
+			})
+			continue
+		}
+
+		change := func(kind, detail string) {
+			changes = append(changes, StationChange{
+				Kind:       kind,
+				StationID:  old.ID,
+				ExternalID: station.ID,
+				Name:       station.Name,
+				Detail:     detail,
+			})
+		}
+
+		if !old.Active {
+			change(ChangeReactivated, "back in the feed")
+		}
+		if old.Name != station.Name {
+			change(ChangeRenamed, fmt.Sprintf("was %q", old.Name))
+		}
+		if d := DistanceMeters(old.Lat, old.Lon, station.Lat, station.Lon); d > relocateMeters {
+			change(ChangeRelocated, fmt.Sprintf("moved %.0f m", d))
+		}
+
+		var oldLines []string
+		if old.Lines != "" {
+			if err := json.Unmarshal([]byte(old.Lines), &oldLines); err != nil {
+				change(ChangeLines, fmt.Sprintf("stored lines unreadable (%v), now %s",
+					err, strings.Join(station.LineNames(), ", ")))
+				continue
+			}
+		}
+		if added, removed := diffLines(oldLines, station.LineNames()); len(added)+len(removed) > 0 {
+			var parts []string
+			if len(added) > 0 {
+				parts = append(parts, "+"+strings.Join(added, ", +"))
+			}
+			if len(removed) > 0 {
+				parts = append(parts, "-"+strings.Join(removed, ", -"))
+			}
+			change(ChangeLines, strings.Join(parts, " "))
+		}
+	}
+
+	for _, s := range stored {
+		if s.Active && !inFeed[s.ExternalID] {
+			changes = append(changes, StationChange{
+				Kind:       ChangeRemoved,
+				StationID:  s.ID,
+				ExternalID: s.ExternalID,
+				Name:       s.Name,
+				Detail:     "no longer in the feed",
+			})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changeOrder(changes[i].Kind) < changeOrder(changes[j].Kind)
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func changeOrder(kind string) int {
+	for i, k := range []string{ChangeAdded, ChangeRemoved, ChangeReactivated, ChangeRenamed, ChangeRelocated, ChangeLines} {
+		if k == kind {
+			return i
+		}
+	}
+	return math.MaxInt
+}
+
+// diffLines returns the lines only in next and only in prev
+func diffLines(prev, next []string) (added, removed []string) {
+	had := make(map[string]bool, len(prev))
+	for _, l := range prev {
+		had[l] = true
+	}
+	has := make(map[string]bool, len(next))
+	for _, l := range next {
+		has[l] = true
+		if !had[l] {
+			added = append(added, l)
+		}
+	}
+	for _, l := range prev {
+		if !has[l] {
+			removed = append(removed, l)
+		}
+	}
+	return added, removed
+}
+
+// PrintChanges writes a station change report
+func PrintChanges(w io.Writer, changes []StationChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No station changes since the last feed")
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[c.Kind]++
+	}
+	fmt.Fprintf(w, "\nStation changes since the last feed: %d added, %d removed, %d reactivated, %d renamed, %d relocated, %d line changes\n",
+		counts[ChangeAdded], counts[ChangeRemoved], counts[ChangeReactivated],
+		counts[ChangeRenamed], counts[ChangeRelocated], counts[ChangeLines])
+	for _, c := range changes {
+		fmt.Fprintf(w, "  %-12s %-35s %-8s %s\n", c.Kind, c.Name, c.ExternalID, c.Detail)
+	}
+}
+
+// DistanceMeters returns the great-circle (haversine) distance between two points
+func DistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,146 @@
+package gtfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+)
+
+func storedStation(id, externalID, name string, lat, lon float64, lines string, active bool) db.StoredStation {
+	return db.StoredStation{ID: id, ExternalID: externalID, Name: name, Lat: lat, Lon: lon, Lines: lines, Active: active}
+}
+
+func feedStation(externalID, name string, lat, lon float64, lines ...string) Station {
+	s := Station{ID: externalID, Name: name, Lat: lat, Lon: lon}
+	for _, l := range lines {
+		s.Lines = append(s.Lines, Line{Name: l})
+	}
+	return s
+}
+
+func TestDiffStations(t *testing.T) {
+	clarkLake := storedStation("s1", "40380", "Clark/Lake", 41.885737, -87.630886, `["Blue","Brown"]`, true)
+	howard := storedStation("s2", "40900", "Howard", 42.019063, -87.672892, `["Red"]`, true)
+
+	tests := []struct {
+		name     string
+		stored   []db.StoredStation
+		stations []Station
+		want     []StationChange
+	}{
+		{
+			name:     "unchanged",
+			stored:   []db.StoredStation{clarkLake},
+			stations: []Station{feedStation("40380", "Clark/Lake", 41.885737, -87.630886, "Blue", "Brown")},
+		},
+		{
+			name:   "added",
+			stored: []db.StoredStation{clarkLake},
+			stations: []Station{
+				feedStation("40380", "Clark/Lake", 41.885737, -87.630886, "Blue", "Brown"),
+				feedStation("41700", "Washington/Wabash", 41.88322, -87.626189, "Brown", "Green"),
+			},
+			want: []StationChange{
+				{Kind: ChangeAdded, ExternalID: "41700", Name: "Washington/Wabash", Detail: "Brown, Green"},
+			},
+		},
+		{
+			name: "removed, but not again once inactive",
+			stored: []db.StoredStation{
+				clarkLake,
+				howard,
+				storedStation("s3", "40640", "Madison/Wabash", 41.882023, -87.626098, `["Brown"]`, false),
+			},
+			stations: []Station{feedStation("40380", "Clark/Lake", 41.885737, -87.630886, "Blue", "Brown")},
+			want: []StationChange{
+				{Kind: ChangeRemoved, StationID: "s2", ExternalID: "40900", Name: "Howard", Detail: "no longer in the feed"},
+			},
+		},
+		{
+			name:     "reactivated",
+			stored:   []db.StoredStation{storedStation("s2", "40900", "Howard", 42.019063, -87.672892, `["Red"]`, false)},
+			stations: []Station{feedStation("40900", "Howard", 42.019063, -87.672892, "Red")},
+			want: []StationChange{
+				{Kind: ChangeReactivated, StationID: "s2", ExternalID: "40900", Name: "Howard", Detail: "back in the feed"},
+			},
+		},
+		{
+			name:     "renamed",
+			stored:   []db.StoredStation{clarkLake},
+			stations: []Station{feedStation("40380", "Clark-Lake", 41.885737, -87.630886, "Blue", "Brown")},
+			want: []StationChange{
+				{Kind: ChangeRenamed, StationID: "s1", ExternalID: "40380", Name: "Clark-Lake", Detail: `was "Clark/Lake"`},
+			},
+		},
+		{
+			name:     "small move ignored",
+			stored:   []db.StoredStation{clarkLake},
+			stations: []Station{feedStation("40380", "Clark/Lake", 41.886237, -87.630886, "Blue", "Brown")},
+		},
+		{
+			name:     "relocated",
+			stored:   []db.StoredStation{clarkLake},
+			stations: []Station{feedStation("40380", "Clark/Lake", 41.887737, -87.630886, "Blue", "Brown")},
+			want: []StationChange{
+				{Kind: ChangeRelocated, StationID: "s1", ExternalID: "40380", Name: "Clark/Lake", Detail: "moved 222 m"},
+			},
+		},
+		{
+			name:     "lines gained and lost",
+			stored:   []db.StoredStation{clarkLake},
+			stations: []Station{feedStation("40380", "Clark/Lake", 41.885737, -87.630886, "Brown", "Green", "Pink")},
+			want: []StationChange{
+				{Kind: ChangeLines, StationID: "s1", ExternalID: "40380", Name: "Clark/Lake", Detail: "+Green, +Pink -Blue"},
+			},
+		},
+		{
+			name:     "no stored lines",
+			stored:   []db.StoredStation{storedStation("s2", "40900", "Howard", 42.019063, -87.672892, "", true)},
+			stations: []Station{feedStation("40900", "Howard", 42.019063, -87.672892, "Red")},
+			want: []StationChange{
+				{Kind: ChangeLines, StationID: "s2", ExternalID: "40900", Name: "Howard", Detail: "+Red"},
+			},
+		},
+		{
+			name:   "several changes, ordered by kind then name",
+			stored: []db.StoredStation{clarkLake, howard},
+			stations: []Station{
+				feedStation("40380", "Clark/Lake", 41.885737, -87.630886, "Blue"),
+				feedStation("41700", "Washington/Wabash", 41.88322, -87.626189, "Brown", "Green"),
+				feedStation("40020", "Harlem/Lake", 41.886848, -87.803176, "Green"),
+			},
+			want: []StationChange{
+				{Kind: ChangeAdded, ExternalID: "40020", Name: "Harlem/Lake", Detail: "Green"},
+				{Kind: ChangeAdded, ExternalID: "41700", Name: "Washington/Wabash", Detail: "Brown, Green"},
+				{Kind: ChangeRemoved, StationID: "s2", ExternalID: "40900", Name: "Howard", Detail: "no longer in the feed"},
+				{Kind: ChangeLines, StationID: "s1", ExternalID: "40380", Name: "Clark/Lake", Detail: "-Brown"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffStations(tt.stored, tt.stations, DefaultRelocateMeters)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("change %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffStationsUnreadableLines(t *testing.T) {
+	stored := []db.StoredStation{storedStation("s1", "40380", "Clark/Lake", 41.885737, -87.630886, "Blue,Brown", true)}
+	stations := []Station{feedStation("40380", "Clark/Lake", 41.885737, -87.630886, "Blue", "Brown")}
+
+	got := DiffStations(stored, stations, DefaultRelocateMeters)
+	if len(got) != 1 || got[0].Kind != ChangeLines ||
+		!strings.HasPrefix(got[0].Detail, "stored lines unreadable") || !strings.HasSuffix(got[0].Detail, "now Blue, Brown") {
+		t.Errorf("got %+v, want one lines change reporting the unreadable stored lines", got)
+	}
+}
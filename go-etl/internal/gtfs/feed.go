@@ -0,0 +1,168 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Feed is an opened GTFS static zip archive
+type Feed struct {
+	zip     *zip.ReadCloser
+	tmpPath string // set when the feed was downloaded and must be removed on Close
+}
+
+// Open opens a GTFS zip from a local path or an http(s) URL
+func Open(source string) (*Feed, error) {
+	feed := &Feed{}
+
+	zipPath := source
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		path, err := downloadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download GTFS: %w", err)
+		}
+		zipPath = path
+		feed.tmpPath = path
+	}
+
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		if feed.tmpPath != "" {
+			os.Remove(feed.tmpPath)
+		}
+		return nil, fmt.Errorf("failed to open zip: %w", err)
+	}
+	feed.zip = r
+
+	return feed, nil
+}
+
+// Close releases the zip and removes any downloaded temp file
+func (f *Feed) Close() error {
+	err := f.zip.Close()
+	if f.tmpPath != "" {
+		os.Remove(f.tmpPath)
+	}
+	return err
+}
+
+// HasFile reports whether the feed contains the named file
+func (f *Feed) HasFile(name string) bool {
+	return f.file(name) != nil
+}
+
+// file finds a file in the archive, allowing feeds zipped inside a folder
+func (f *Feed) file(name string) *zip.File {
+	for _, zf := range f.zip.File {
+		if zf.Name == name || strings.HasSuffix(zf.Name, "/"+name) {
+			return zf
+		}
+	}
+	return nil
+}
+
+// record is a single CSV row with access by column name
+type record struct {
+	colIndex map[string]int
+	fields   []string
+}
+
+func (r record) get(col string) string {
+	i, ok := r.colIndex[col]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+func (r record) getInt(col string) int {
+	v, _ := strconv.Atoi(r.get(col))
+	return v
+}
+
+func (r record) getFloat(col string) float64 {
+	v, _ := strconv.ParseFloat(r.get(col), 64)
+	return v
+}
+
+// readCSV streams a GTFS file row by row, checking required columns first
+func (f *Feed) readCSV(name string, required []string, fn func(r record) error) error {
+	zf := f.file(name)
+	if zf == nil {
+		return fmt.Errorf("%s not found in GTFS zip", name)
+	}
+
+	rc, err := zf.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read %s header: %w", name, err)
+	}
+
+	colIndex := make(map[string]int, len(header))
+	for i, col := range header {
+		// Some agencies ship a UTF-8 BOM on the first column
+		colIndex[strings.TrimPrefix(strings.TrimSpace(col), "\ufeff")] = i
+	}
+
+	for _, col := range required {
+		if _, ok := colIndex[col]; !ok {
+			return fmt.Errorf("%s is missing required column: %s", name, col)
+		}
+	}
+
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+
+		if err := fn(record{colIndex: colIndex, fields: fields}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// downloadFile downloads a URL into a temp file and returns its path
+func downloadFile(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d downloading %s", resp.StatusCode, url)
+	}
+
+	out, err := os.CreateTemp("", "gtfs-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
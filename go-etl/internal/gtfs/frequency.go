@@ -0,0 +1,79 @@
+package gtfs
+
+import "time"
+
+// ServiceFrequency is the average number of scheduled trips stopping at a
+// station on each day type over the feed's calendar range.
+type ServiceFrequency struct {
+	Weekday  float64
+	Saturday float64
+	Sunday   float64
+}
+
+// DailyAverage weights the day types into an average day of the week
+func (s ServiceFrequency) DailyAverage() float64 {
+	return (5*s.Weekday + s.Saturday + s.Sunday) / 7
+}
+
+// maxCalendarYears caps how much of the calendar dayTypeWeights walks, so feeds
+// with placeholder end dates years out don't cost a pass over every one of those days
+const maxCalendarYears = 1
+
+// dayTypeWeights returns, for each day type, the fraction of that day type's
+// dates on which each service_id runs, over the calendar's first year at most.
+// A station's frequency for a day type is then sum(trips on service * weight of
+// service). end is the last date averaged over.
+func dayTypeWeights(cal *Calendar) (weekday, saturday, sunday map[string]float64, end time.Time) {
+	weekday = make(map[string]float64)
+	saturday = make(map[string]float64)
+	sunday = make(map[string]float64)
+
+	end = cal.End
+	if limit := cal.Start.AddDate(maxCalendarYears, 0, -1); end.After(limit) {
+		end = limit
+	}
+
+	var nWeekday, nSaturday, nSunday int
+	for d := cal.Start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		var target map[string]float64
+		switch d.Weekday() {
+		case time.Saturday:
+			target = saturday
+			nSaturday++
+		case time.Sunday:
+			target = sunday
+			nSunday++
+		default:
+			target = weekday
+			nWeekday++
+		}
+		for id := range cal.ServicesOn(d) {
+			target[id]++
+		}
+	}
+
+	scale(weekday, nWeekday)
+	scale(saturday, nSaturday)
+	scale(sunday, nSunday)
+	return weekday, saturday, sunday, end
+}
+
+func scale(weights map[string]float64, n int) {
+	if n == 0 {
+		return
+	}
+	for id := range weights {
+		weights[id] /= float64(n)
+	}
+}
+
+// serviceFrequency converts per-service trip counts into per-day-type averages
+func serviceFrequency(tripsByService map[string]int, weekday, saturday, sunday map[string]float64) ServiceFrequency {
+	var freq ServiceFrequency
+	for serviceID, trips := range tripsByService {
+		freq.Weekday += float64(trips) * weekday[serviceID]
+		freq.Saturday += float64(trips) * saturday[serviceID]
+		freq.Sunday += float64(trips) * sunday[serviceID]
+	}
+	return freq
+}
@@ -0,0 +1,148 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFeed zips files (name -> contents) into a feed in a temporary directory
+func writeFeed(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "feed.zip")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const calendarHeader = "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n"
+
+func TestDayTypeWeights(t *testing.T) {
+	tests := []struct {
+		name          string
+		calendar      string // calendar.txt rows
+		calendarDates string // calendar_dates.txt rows
+		weekday       map[string]float64
+		saturday      map[string]float64
+		sunday        map[string]float64
+		end           string
+	}{
+		{
+			name: "weekly patterns",
+			calendar: "WK,1,1,1,1,1,0,0,20260105,20260118\n" +
+				"SA,0,0,0,0,0,1,0,20260105,20260118\n" +
+				"SU,0,0,0,0,0,0,1,20260105,20260118\n",
+			weekday:  map[string]float64{"WK": 1},
+			saturday: map[string]float64{"SA": 1},
+			sunday:   map[string]float64{"SU": 1},
+			end:      "2026-01-18",
+		},
+		{
+			// A Wednesday holiday runs the Sunday service instead of the weekday one
+			name: "holiday exceptions",
+			calendar: "WK,1,1,1,1,1,0,0,20260105,20260118\n" +
+				"SA,0,0,0,0,0,1,0,20260105,20260118\n" +
+				"SU,0,0,0,0,0,0,1,20260105,20260118\n",
+			calendarDates: "WK,20260107,2\nSU,20260107,1\n",
+			weekday:       map[string]float64{"WK": 0.9, "SU": 0.1},
+			saturday:      map[string]float64{"SA": 1},
+			sunday:        map[string]float64{"SU": 1},
+			end:           "2026-01-18",
+		},
+		{
+			name:          "calendar_dates only",
+			calendarDates: "EXTRA,20260110,1\nEXTRA,20260117,1\n",
+			weekday:       map[string]float64{},
+			saturday:      map[string]float64{"EXTRA": 1},
+			sunday:        map[string]float64{},
+			end:           "2026-01-17",
+		},
+		{
+			// Only the first year is averaged over, so the far-off exception is ignored
+			name:          "placeholder end date",
+			calendar:      "WK,1,1,1,1,1,0,0,20260105,20991231\n",
+			calendarDates: "WK,20900102,2\n",
+			weekday:       map[string]float64{"WK": 1},
+			saturday:      map[string]float64{},
+			sunday:        map[string]float64{},
+			end:           "2027-01-04",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := make(map[string]string)
+			if tt.calendar != "" {
+				files["calendar.txt"] = calendarHeader + tt.calendar
+			}
+			if tt.calendarDates != "" {
+				files["calendar_dates.txt"] = "service_id,date,exception_type\n" + tt.calendarDates
+			}
+			f, err := Open(writeFeed(t, files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			cal, err := f.Calendar()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			weekday, saturday, sunday, end := dayTypeWeights(cal)
+			checkWeights(t, "weekday", weekday, tt.weekday)
+			checkWeights(t, "saturday", saturday, tt.saturday)
+			checkWeights(t, "sunday", sunday, tt.sunday)
+			if got := end.Format("2006-01-02"); got != tt.end {
+				t.Errorf("end: got %s, want %s", got, tt.end)
+			}
+		})
+	}
+}
+
+func checkWeights(t *testing.T, dayType string, got, want map[string]float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s weights: got %v, want %v", dayType, got, want)
+		return
+	}
+	for id, w := range want {
+		if diff := got[id] - w; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s weights: got %v, want %v", dayType, got, want)
+			return
+		}
+	}
+}
+
+func TestServiceFrequency(t *testing.T) {
+	weekday := map[string]float64{"WK": 0.9, "SU": 0.1}
+	saturday := map[string]float64{"SA": 1}
+	sunday := map[string]float64{"SU": 1}
+
+	freq := serviceFrequency(map[string]int{"WK": 100, "SA": 60, "SU": 40}, weekday, saturday, sunday)
+	want := ServiceFrequency{Weekday: 94, Saturday: 60, Sunday: 40}
+	if freq != want {
+		t.Errorf("got %+v, want %+v", freq, want)
+	}
+	if avg := freq.DailyAverage(); avg != (5*94+60+40)/7.0 {
+		t.Errorf("DailyAverage: got %v", avg)
+	}
+}
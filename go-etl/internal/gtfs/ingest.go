@@ -0,0 +1,136 @@
+package gtfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+// Ingest loads a GTFS feed's rail stations and routes into the database for a city.
+// It returns the number of stations written.
+func Ingest(dbClient *db.Client, cityID, source string, opts Options) (int, error) {
+	feed, err := Open(source)
+	if err != nil {
+		return 0, err
+	}
+	defer feed.Close()
+
+	network, err := feed.RailStations(opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to derive rail stations: %w", err)
+	}
+	stations := network.Stations
+
+	// Compare with what the previous feed left in the database before overwriting it
+	stored, err := dbClient.GetGTFSStations(cityID)
+	if err != nil {
+		return 0, err
+	}
+	relocateMeters := opts.RelocateMeters
+	if relocateMeters <= 0 {
+		relocateMeters = DefaultRelocateMeters
+	}
+	changes := DiffStations(stored, stations, relocateMeters)
+
+	// Persist every rail route referenced by a station, with its color
+	routesWritten := make(map[string]bool)
+	for _, station := range stations {
+		for _, line := range station.Lines {
+			if routesWritten[line.RouteID] {
+				continue
+			}
+			err := dbClient.UpsertRoute(cityID, db.Route{
+				ExternalID: line.RouteID,
+				Name:       line.Name,
+				Color:      line.Color,
+				TextColor:  line.TextColor,
+				RouteType:  line.RouteType,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to upsert route %s: %w", line.RouteID, err)
+			}
+			routesWritten[line.RouteID] = true
+		}
+	}
+
+	normalizer := opts.Normalizer
+	if normalizer == nil {
+		normalizer = normalize.Default()
+	}
+
+	insertCount := 0
+	serviceCount := 0
+	for _, station := range stations {
+		linesJSON, _ := json.Marshal(station.LineNames())
+
+		err = dbClient.UpsertStation(
+			cityID,
+			station.ID,
+			station.Name,
+			station.Lat,
+			station.Lon,
+			string(linesJSON),
+		)
+		if err != nil {
+			fmt.Printf("Warning: Failed to insert station %s: %v\n", station.Name, err)
+			continue
+		}
+		insertCount++
+
+		// Get the station's UUID we just inserted/updated
+		stationUUID, err := dbClient.GetStationIDByExternalID(cityID, station.ID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to get UUID for station %s: %v\n", station.Name, err)
+			continue
+		}
+
+		// Create normalized alias for the station
+		normalized := normalizer.Normalize(station.Name)
+		dbClient.CreateStationAlias(stationUUID, station.Name, normalized)
+
+		if station.Service != nil {
+			err = dbClient.UpsertStationService(db.StationService{
+				StationID:     stationUUID,
+				WeekdayTrips:  station.Service.Weekday,
+				SaturdayTrips: station.Service.Saturday,
+				SundayTrips:   station.Service.Sunday,
+				FeedStartDate: network.ServiceStart.Format("2006-01-02"),
+				FeedEndDate:   network.ServiceEnd.Format("2006-01-02"),
+			})
+			if err != nil {
+				fmt.Printf("Warning: Failed to store service frequency for station %s: %v\n", station.Name, err)
+			} else {
+				serviceCount++
+			}
+		}
+	}
+
+	deactivated := 0
+	for _, c := range changes {
+		if c.Kind != ChangeRemoved || !opts.DeactivateRemoved {
+			continue
+		}
+		if err := dbClient.DeactivateStation(c.StationID); err != nil {
+			fmt.Printf("Warning: Failed to deactivate station %s: %v\n", c.Name, err)
+			continue
+		}
+		deactivated++
+	}
+
+	if len(stored) > 0 {
+		PrintChanges(os.Stdout, changes)
+		if deactivated > 0 {
+			fmt.Printf("Marked %d removed stations inactive\n", deactivated)
+		}
+	}
+
+	fmt.Printf("Processed %d rail routes\n", len(routesWritten))
+	if serviceCount > 0 {
+		fmt.Printf("Stored scheduled service for %d stations (%s to %s)\n",
+			serviceCount, network.ServiceStart.Format("2006-01-02"), network.ServiceEnd.Format("2006-01-02"))
+	}
+	return insertCount, nil
+}
@@ -0,0 +1,131 @@
+package gtfs
+
+import "strings"
+
+// Route is a row from routes.txt
+type Route struct {
+	ID        string
+	ShortName string
+	LongName  string
+	Type      int
+	Color     string // hex without leading '#'
+	TextColor string
+	SortOrder int // route_sort_order, or file position when absent
+}
+
+// DisplayName returns the short name when present, otherwise the long name
+// with a trailing " Line" removed (e.g. "Red Line" -> "Red")
+func (r Route) DisplayName() string {
+	if r.ShortName != "" {
+		return r.ShortName
+	}
+	return strings.TrimSuffix(r.LongName, " Line")
+}
+
+// IsRail reports whether a route_type is rail service: tram/light rail (0),
+// subway/metro (1) and rail (2), plus the equivalent extended route types.
+func IsRail(routeType int) bool {
+	switch {
+	case routeType >= 0 && routeType <= 2:
+		return true
+	case routeType >= 100 && routeType < 200: // railway service
+		return true
+	case routeType >= 400 && routeType < 500: // urban railway service
+		return true
+	case routeType >= 900 && routeType < 1000: // tram service
+		return true
+	}
+	return false
+}
+
+// Stop is a row from stops.txt
+type Stop struct {
+	ID            string
+	Name          string
+	Desc          string
+	Lat           float64
+	Lon           float64
+	LocationType  int
+	ParentStation string
+}
+
+// Trip is a row from trips.txt
+type Trip struct {
+	ID        string
+	RouteID   string
+	ServiceID string
+}
+
+// StopTime is the subset of stop_times.txt needed for station service
+type StopTime struct {
+	TripID string
+	StopID string
+}
+
+// Routes reads routes.txt keyed by route_id
+func (f *Feed) Routes() (map[string]Route, error) {
+	routes := make(map[string]Route)
+	position := 0
+	err := f.readCSV("routes.txt", []string{"route_id", "route_type"}, func(r record) error {
+		position++
+		route := Route{
+			ID:        r.get("route_id"),
+			ShortName: r.get("route_short_name"),
+			LongName:  r.get("route_long_name"),
+			Type:      r.getInt("route_type"),
+			Color:     strings.TrimPrefix(r.get("route_color"), "#"),
+			TextColor: strings.TrimPrefix(r.get("route_text_color"), "#"),
+			SortOrder: position,
+		}
+		if r.get("route_sort_order") != "" {
+			route.SortOrder = r.getInt("route_sort_order")
+		}
+		routes[route.ID] = route
+		return nil
+	})
+	return routes, err
+}
+
+// Stops reads stops.txt keyed by stop_id
+func (f *Feed) Stops() (map[string]Stop, error) {
+	stops := make(map[string]Stop)
+	err := f.readCSV("stops.txt", []string{"stop_id", "stop_name", "stop_lat", "stop_lon"}, func(r record) error {
+		stop := Stop{
+			ID:            r.get("stop_id"),
+			Name:          r.get("stop_name"),
+			Desc:          r.get("stop_desc"),
+			Lat:           r.getFloat("stop_lat"),
+			Lon:           r.getFloat("stop_lon"),
+			LocationType:  r.getInt("location_type"),
+			ParentStation: r.get("parent_station"),
+		}
+		stops[stop.ID] = stop
+		return nil
+	})
+	return stops, err
+}
+
+// Trips reads trips.txt keyed by trip_id
+func (f *Feed) Trips() (map[string]Trip, error) {
+	trips := make(map[string]Trip)
+	err := f.readCSV("trips.txt", []string{"route_id", "service_id", "trip_id"}, func(r record) error {
+		trip := Trip{
+			ID:        r.get("trip_id"),
+			RouteID:   r.get("route_id"),
+			ServiceID: r.get("service_id"),
+		}
+		trips[trip.ID] = trip
+		return nil
+	})
+	return trips, err
+}
+
+// EachStopTime streams stop_times.txt, which is too large to hold in memory for most agencies
+func (f *Feed) EachStopTime(fn func(st StopTime) error) error {
+	return f.readCSV("stop_times.txt", []string{"trip_id", "stop_id"}, func(r record) error {
+		return fn(StopTime{
+			TripID: r.get("trip_id"),
+			StopID: r.get("stop_id"),
+		})
+	})
+}
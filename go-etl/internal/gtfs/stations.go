@@ -0,0 +1,224 @@
+package gtfs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+// Line is a rail route serving a station
+type Line struct {
+	RouteID   string
+	Name      string
+	Color     string
+	TextColor string
+	RouteType int
+	sortOrder int
+}
+
+// Station is a parent station served by at least one rail route
+type Station struct {
+	ID    string // parent stop_id, or the stop's own ID when it has no parent
+	Name  string
+	Lat   float64
+	Lon   float64
+	Lines []Line
+
+	// Service is nil when the feed has no calendar to resolve service_ids against
+	Service *ServiceFrequency
+}
+
+// LineNames returns the station's line names in route order
+func (s Station) LineNames() []string {
+	names := make([]string, 0, len(s.Lines))
+	for _, l := range s.Lines {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// Options customizes how a feed's routes are presented
+type Options struct {
+	// LineName overrides Route.DisplayName for naming lines (e.g. agency abbreviations)
+	LineName func(Route) string
+
+	// RelocateMeters is how far a station must move to be reported (DefaultRelocateMeters when 0)
+	RelocateMeters float64
+	// DeactivateRemoved marks stations missing from the feed inactive instead of only reporting them
+	DeactivateRemoved bool
+
+	// Normalizer normalizes station names into their aliases (normalize.Default() when nil)
+	Normalizer *normalize.Normalizer
+}
+
+func (o Options) lineName(r Route) string {
+	if o.LineName != nil {
+		if name := o.LineName(r); name != "" {
+			return name
+		}
+	}
+	return r.DisplayName()
+}
+
+// Network is the rail portion of a feed: its stations and the calendar range
+// the scheduled service frequencies were averaged over.
+type Network struct {
+	Stations     []Station
+	ServiceStart time.Time
+	ServiceEnd   time.Time
+}
+
+// RailStations derives which parent stations are served by which rail routes,
+// and how often, by walking trips.txt, stop_times.txt and the service calendar.
+func (f *Feed) RailStations(opts Options) (*Network, error) {
+	routes, err := f.Routes()
+	if err != nil {
+		return nil, err
+	}
+
+	railRoutes := make(map[string]Route)
+	for id, r := range routes {
+		if IsRail(r.Type) {
+			railRoutes[id] = r
+		}
+	}
+	if len(railRoutes) == 0 {
+		return nil, fmt.Errorf("no rail routes (route_type 0/1/2) found in feed")
+	}
+
+	trips, err := f.Trips()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rail trips only; bus trips are the bulk of most feeds
+	railTrips := make(map[string]Trip)
+	for id, t := range trips {
+		if _, ok := railRoutes[t.RouteID]; ok {
+			railTrips[id] = t
+		}
+	}
+
+	stops, err := f.Stops()
+	if err != nil {
+		return nil, err
+	}
+
+	// parent station -> set of route IDs
+	served := make(map[string]map[string]bool)
+	// parent station -> service_id -> distinct trips stopping there
+	tripsByService := make(map[string]map[string]int)
+	// A trip can stop at a station twice (e.g. around the Loop); count it once
+	counted := make(map[[2]string]bool)
+
+	err = f.EachStopTime(func(st StopTime) error {
+		trip, ok := railTrips[st.TripID]
+		if !ok {
+			return nil
+		}
+		parentID := stationID(stops, st.StopID)
+		if served[parentID] == nil {
+			served[parentID] = make(map[string]bool)
+			tripsByService[parentID] = make(map[string]int)
+		}
+		served[parentID][trip.RouteID] = true
+
+		key := [2]string{trip.ID, parentID}
+		if !counted[key] {
+			counted[key] = true
+			tripsByService[parentID][trip.ServiceID]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	network := &Network{}
+
+	var weekday, saturday, sunday map[string]float64
+	cal, err := f.Calendar()
+	if err != nil {
+		fmt.Printf("Warning: Skipping service frequency: %v\n", err)
+	} else {
+		weekday, saturday, sunday, network.ServiceEnd = dayTypeWeights(cal)
+		network.ServiceStart = cal.Start
+	}
+
+	stations := make([]Station, 0, len(served))
+	for parentID, routeIDs := range served {
+		station := Station{ID: parentID}
+		if stop, ok := stops[parentID]; ok {
+			station.Name = stop.Name
+			station.Lat = stop.Lat
+			station.Lon = stop.Lon
+		}
+		if station.Lat == 0 && station.Lon == 0 {
+			station.Lat, station.Lon = childLocation(stops, parentID)
+		}
+
+		for routeID := range routeIDs {
+			r := railRoutes[routeID]
+			station.Lines = append(station.Lines, Line{
+				RouteID:   r.ID,
+				Name:      opts.lineName(r),
+				Color:     r.Color,
+				TextColor: r.TextColor,
+				RouteType: r.Type,
+				sortOrder: r.SortOrder,
+			})
+		}
+		sort.Slice(station.Lines, func(i, j int) bool {
+			if station.Lines[i].sortOrder != station.Lines[j].sortOrder {
+				return station.Lines[i].sortOrder < station.Lines[j].sortOrder
+			}
+			return station.Lines[i].RouteID < station.Lines[j].RouteID
+		})
+		station.Lines = dedupeLineNames(station.Lines)
+
+		if cal != nil {
+			freq := serviceFrequency(tripsByService[parentID], weekday, saturday, sunday)
+			station.Service = &freq
+		}
+
+		stations = append(stations, station)
+	}
+
+	sort.Slice(stations, func(i, j int) bool { return stations[i].ID < stations[j].ID })
+	network.Stations = stations
+	return network, nil
+}
+
+// stationID resolves a platform stop to its parent station
+func stationID(stops map[string]Stop, stopID string) string {
+	if stop, ok := stops[stopID]; ok && stop.ParentStation != "" {
+		return stop.ParentStation
+	}
+	return stopID
+}
+
+// childLocation falls back to the first child stop's coordinates for parents without any
+func childLocation(stops map[string]Stop, parentID string) (float64, float64) {
+	for _, s := range stops {
+		if s.ParentStation == parentID && (s.Lat != 0 || s.Lon != 0) {
+			return s.Lat, s.Lon
+		}
+	}
+	return 0, 0
+}
+
+// dedupeLineNames drops routes that present under the same line name (e.g. express variants)
+func dedupeLineNames(lines []Line) []Line {
+	seen := make(map[string]bool)
+	out := lines[:0]
+	for _, l := range lines {
+		if seen[l.Name] {
+			continue
+		}
+		seen[l.Name] = true
+		out = append(out, l)
+	}
+	return out
+}
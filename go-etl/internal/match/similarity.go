@@ -0,0 +1,161 @@
+// Package match provides string similarity measures for fuzzy station name matching.
+// All measures return a value between 0 (nothing in common) and 1 (identical) and
+// expect names that have already been normalized.
+package match
+
+import (
+	"sort"
+	"strings"
+)
+
+// Levenshtein returns the number of single-character insertions, deletions and
+// substitutions needed to turn a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// EditSimilarity is Levenshtein distance scaled by the longer string's length
+func EditSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// JaroWinkler returns the Jaro similarity of a and b, boosted for a shared prefix
+// of up to four characters
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Count matched characters that appear in a different order
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions/2))/m) / 3
+
+	prefix := 0
+	for prefix < 4 && prefix < len(ra) && prefix < len(rb) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// TokenSetSimilarity compares the words of a and b regardless of order or
+// repetition. Words the names share count in full, so a name whose words are all
+// contained in the other scores 1.
+func TokenSetSimilarity(a, b string) float64 {
+	setA, setB := tokenSet(a), tokenSet(b)
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+
+	var common, onlyA, onlyB []string
+	for t := range setA {
+		if setB[t] {
+			common = append(common, t)
+		} else {
+			onlyA = append(onlyA, t)
+		}
+	}
+	for t := range setB {
+		if !setA[t] {
+			onlyB = append(onlyB, t)
+		}
+	}
+	sort.Strings(common)
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+
+	shared := strings.Join(common, " ")
+	withA := strings.TrimSpace(shared + " " + strings.Join(onlyA, " "))
+	withB := strings.TrimSpace(shared + " " + strings.Join(onlyB, " "))
+
+	best := EditSimilarity(withA, withB)
+	if shared != "" {
+		best = max(best, EditSimilarity(shared, withA), EditSimilarity(shared, withB))
+	}
+	return best
+}
+
+// SortTokens returns a name's words in sorted order, so that character-level
+// measures ignore word order
+func SortTokens(s string) string {
+	tokens := strings.Fields(s)
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// tokenSet splits a name into its distinct words
+func tokenSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range strings.Fields(s) {
+		set[t] = true
+	}
+	return set
+}
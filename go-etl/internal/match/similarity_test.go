@@ -0,0 +1,102 @@
+package match
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-3
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "howard", 6},
+		{"kitten", "sitting", 3},
+		{"cermak", "cermak", 0},
+		{"o'hare", "ohare", 1},
+		{"jefferson park", "jeferson park", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEditSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"howard", "", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"belmont", "belmont", 1},
+	}
+	for _, tt := range tests {
+		if got := EditSimilarity(tt.a, tt.b); !approx(got, tt.want) {
+			t.Errorf("EditSimilarity(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"howard", "", 0},
+		{"abc", "xyz", 0},
+		{"belmont", "belmont", 1},
+		// Reference values from Winkler's paper
+		{"martha", "marhta", 0.9611},
+		{"dwayne", "duane", 0.84},
+		{"dixon", "dicksonx", 0.8133},
+		// The prefix boost: same Jaro, but only the first pair shares a prefix
+		{"montrose", "montroes", 0.975},
+		{"esormont", "seormont", 0.9583},
+	}
+	for _, tt := range tests {
+		if got := JaroWinkler(tt.a, tt.b); !approx(got, tt.want) {
+			t.Errorf("JaroWinkler(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+		if got, rev := JaroWinkler(tt.a, tt.b), JaroWinkler(tt.b, tt.a); !approx(got, rev) {
+			t.Errorf("JaroWinkler(%q, %q) = %.4f but reversed = %.4f", tt.a, tt.b, got, rev)
+		}
+	}
+}
+
+func TestTokenSetSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"howard", "", 0},
+		{"clark lake", "lake clark", 1},
+		{"clark clark lake", "lake clark", 1},
+		// Every word of one name is in the other
+		{"washington", "washington wabash", 1},
+		// No words in common: edit similarity of the sorted names
+		{"howard", "harlem", 1 - 5.0/6},
+		// Shared word, differing rest: the shared word against the nearer name wins
+		{"harlem lake", "harlem congress", EditSimilarity("harlem", "harlem lake")},
+	}
+	for _, tt := range tests {
+		if got := TokenSetSimilarity(tt.a, tt.b); !approx(got, tt.want) {
+			t.Errorf("TokenSetSimilarity(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortTokens(t *testing.T) {
+	if got := SortTokens("  lake   clark "); got != "clark lake" {
+		t.Errorf("SortTokens = %q, want %q", got, "clark lake")
+	}
+}
@@ -0,0 +1,92 @@
+package naming
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+// UnresolvedAlias is an alias whose station couldn't be identified
+type UnresolvedAlias struct {
+	Alias
+	Reason string
+}
+
+// ImportResult summarizes an alias import
+type ImportResult struct {
+	Added      int // New StationAlias rows
+	Existing   int // Aliases that were already stored
+	Unresolved []UnresolvedAlias
+}
+
+// ImportAliases stores aliases as StationAlias rows. Each alias's station is found
+// by normalized GTFS name, or failing that by the one station whose normalized
+// name starts with it (so "Belmont" finds "Belmont (Red/Brown/Purple)"). Aliases
+// that match no station or several are reported rather than guessed. Names are
+// normalized with the city's normalizer.
+func ImportAliases(dbClient *db.Client, cityID string, aliases []Alias, n *normalize.Normalizer) (ImportResult, error) {
+	stations, err := dbClient.GetStations(cityID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	stored, err := dbClient.GetStationAliasRecords(cityID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	exists := make(map[[2]string]bool, len(stored))
+	for _, a := range stored {
+		exists[[2]string{a.StationID, a.AliasName}] = true
+	}
+
+	byName := make(map[string][]db.StationRef)
+	for _, s := range stations {
+		normalized := n.Normalize(s.Name)
+		byName[normalized] = append(byName[normalized], s)
+	}
+
+	var result ImportResult
+	for _, alias := range aliases {
+		target := n.Normalize(alias.Station)
+		matches := byName[target]
+		if len(matches) == 0 {
+			for normalized, named := range byName {
+				if strings.HasPrefix(normalized, target+" ") {
+					matches = append(matches, named...)
+				}
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			result.Unresolved = append(result.Unresolved, UnresolvedAlias{alias, "no station named " + alias.Station})
+			continue
+		case 1:
+		default:
+			names := make([]string, len(matches))
+			for i, m := range matches {
+				names[i] = m.Name
+			}
+			sort.Strings(names)
+			result.Unresolved = append(result.Unresolved, UnresolvedAlias{alias,
+				fmt.Sprintf("%q matches %d stations: %s", alias.Station, len(matches), strings.Join(names, ", "))})
+			continue
+		}
+
+		if exists[[2]string{matches[0].ID, alias.Name}] {
+			result.Existing++
+			continue
+		}
+		err := dbClient.CreateStationAlias(matches[0].ID, alias.Name, n.Normalize(alias.Name))
+		if err != nil {
+			return result, fmt.Errorf("failed to create alias %q -> %q: %w", alias.Name, alias.Station, err)
+		}
+		exists[[2]string{matches[0].ID, alias.Name}] = true
+		result.Added++
+	}
+
+	return result, nil
+}
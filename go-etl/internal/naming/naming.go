@@ -0,0 +1,366 @@
+// Package naming loads a city's station naming knowledge (GTFS line names, line
+// hints from ridership name suffixes, special-case mappings, aliases and name
+// normalization rules) from versioned CSV files, so it can be maintained without
+// recompiling.
+//
+// Each city has a directory cities/<city>/ holding any of lines.csv,
+// line_hints.csv, special_mappings.csv, aliases.csv, normalization.csv,
+// abbreviations.csv and stop_words.csv. Every file starts with a
+// "# version: N" line, may carry further "#" comment lines and then has a header
+// row naming its columns.
+package naming
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nate/ghost-stops/go-etl/cities"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+// DirEnv overrides the directory city naming files are read from
+const DirEnv = "GHOST_STOPS_CITIES_DIR"
+
+// DefaultDir is where naming files are read from when DirEnv is unset and the
+// directory exists; otherwise the copies embedded at build time are used
+const DefaultDir = "cities"
+
+// Naming files within a city's directory
+const (
+	LinesFile           = "lines.csv"
+	LineHintsFile       = "line_hints.csv"
+	SpecialMappingsFile = "special_mappings.csv"
+	AliasesFile         = "aliases.csv"
+	NormalizationFile   = "normalization.csv"
+	AbbreviationsFile   = "abbreviations.csv"
+	StopWordsFile       = "stop_words.csv"
+)
+
+// Alias maps a ridership source's name for a station to the station's GTFS name
+type Alias struct {
+	Name    string
+	Station string
+}
+
+// Data is a city's station naming knowledge
+type Data struct {
+	City     string
+	Source   string         // Directory the files were read from, or "embedded"
+	Versions map[string]int // File name -> version; absent files are omitted
+
+	Lines           map[string]string   // GTFS route ID -> line name
+	LineHints       map[string]string   // Source name suffix -> line name
+	SpecialMappings map[string][]string // Source name -> possible GTFS station names
+	Aliases         []Alias
+
+	// Normalization is the city's name normalization config; rules and stop words
+	// not given by its files come from normalize.DefaultConfig
+	Normalization normalize.Config
+	// Normalizer is Normalization compiled, shared by alias storage and matching
+	Normalizer *normalize.Normalizer
+}
+
+// Load reads a city's naming files from DirEnv, else DefaultDir, else the
+// embedded copies
+func Load(city string) (*Data, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return LoadDir(filepath.Join(dir, city), city)
+	}
+	if info, err := os.Stat(filepath.Join(DefaultDir, city)); err == nil && info.IsDir() {
+		return LoadDir(filepath.Join(DefaultDir, city), city)
+	}
+
+	sub, err := fs.Sub(cities.FS, city)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded naming data for %s: %w", city, err)
+	}
+	data, err := load(sub, city)
+	if err != nil {
+		return nil, err
+	}
+	data.Source = "embedded"
+	return data, nil
+}
+
+// LoadDir reads a city's naming files from dir
+func LoadDir(dir, city string) (*Data, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open naming data for %s: %w", city, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("naming data for %s: %s is not a directory", city, dir)
+	}
+
+	data, err := load(os.DirFS(dir), city)
+	if err != nil {
+		return nil, err
+	}
+	data.Source = dir
+	return data, nil
+}
+
+func load(fsys fs.FS, city string) (*Data, error) {
+	d := &Data{
+		City:            city,
+		Versions:        make(map[string]int),
+		Lines:           make(map[string]string),
+		LineHints:       make(map[string]string),
+		SpecialMappings: make(map[string][]string),
+		Normalization:   normalize.Config{Abbreviations: make(map[string]string)},
+	}
+
+	tables := []struct {
+		file    string
+		columns []string
+		add     func(row []string) error
+	}{
+		{LinesFile, []string{"route_id", "line"}, func(row []string) error {
+			if _, dup := d.Lines[row[0]]; dup {
+				return fmt.Errorf("duplicate route %q", row[0])
+			}
+			d.Lines[row[0]] = row[1]
+			return nil
+		}},
+		{LineHintsFile, []string{"suffix", "line"}, func(row []string) error {
+			if _, dup := d.LineHints[row[0]]; dup {
+				return fmt.Errorf("duplicate suffix %q", row[0])
+			}
+			d.LineHints[row[0]] = row[1]
+			return nil
+		}},
+		{SpecialMappingsFile, []string{"source_name", "station_name"}, func(row []string) error {
+			for _, name := range d.SpecialMappings[row[0]] {
+				if name == row[1] {
+					return fmt.Errorf("duplicate mapping %q -> %q", row[0], row[1])
+				}
+			}
+			d.SpecialMappings[row[0]] = append(d.SpecialMappings[row[0]], row[1])
+			return nil
+		}},
+		{AliasesFile, []string{"alias", "station"}, func(row []string) error {
+			d.Aliases = append(d.Aliases, Alias{Name: row[0], Station: row[1]})
+			return nil
+		}},
+		{NormalizationFile, []string{"rule"}, func(row []string) error {
+			d.Normalization.Rules = append(d.Normalization.Rules, row[0])
+			return nil
+		}},
+		{AbbreviationsFile, []string{"abbreviation", "expansion"}, func(row []string) error {
+			abbr := strings.ToLower(row[0])
+			if _, dup := d.Normalization.Abbreviations[abbr]; dup {
+				return fmt.Errorf("duplicate abbreviation %q", row[0])
+			}
+			d.Normalization.Abbreviations[abbr] = strings.ToLower(row[1])
+			return nil
+		}},
+		{StopWordsFile, []string{"word"}, func(row []string) error {
+			d.Normalization.StopWords = append(d.Normalization.StopWords, strings.ToLower(row[0]))
+			return nil
+		}},
+	}
+
+	for _, t := range tables {
+		f, err := fsys.Open(t.file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s/%s: %w", city, t.file, err)
+		}
+		version, err := readTable(f, t.columns, t.add)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s/%s: %w", city, t.file, err)
+		}
+		d.Versions[t.file] = version
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid naming data for %s: %w", city, err)
+	}
+
+	defaults := normalize.DefaultConfig()
+	if _, ok := d.Versions[NormalizationFile]; !ok {
+		d.Normalization.Rules = defaults.Rules
+	}
+	if _, ok := d.Versions[StopWordsFile]; !ok && slices.Contains(d.Normalization.Rules, normalize.StopWords) {
+		d.Normalization.StopWords = defaults.StopWords
+	}
+	normalizer, err := normalize.New(d.Normalization)
+	if err != nil {
+		return nil, fmt.Errorf("invalid normalization rules for %s: %w", city, err)
+	}
+	d.Normalizer = normalizer
+	return d, nil
+}
+
+// Validate checks that line hints name known lines and that no alias maps to two
+// different stations
+func (d *Data) Validate() error {
+	if len(d.Lines) > 0 {
+		known := make(map[string]bool, len(d.Lines))
+		for _, line := range d.Lines {
+			known[line] = true
+		}
+		for suffix, line := range d.LineHints {
+			if !known[line] {
+				return fmt.Errorf("%s: suffix %q names unknown line %q", LineHintsFile, suffix, line)
+			}
+		}
+	}
+
+	return validateAliases(d.Aliases)
+}
+
+// validateAliases rejects an alias that maps to two different stations
+func validateAliases(aliases []Alias) error {
+	stations := make(map[string]string, len(aliases))
+	for _, a := range aliases {
+		if prev, ok := stations[a.Name]; ok && prev != a.Station {
+			return fmt.Errorf("%s: alias %q maps to both %q and %q", AliasesFile, a.Name, prev, a.Station)
+		}
+		stations[a.Name] = a.Station
+	}
+	return nil
+}
+
+// Version returns the version of one of the city's files, or 0 when it is absent
+func (d *Data) Version(file string) int {
+	return d.Versions[file]
+}
+
+// ReadAliases reads and validates an aliases file in the format of aliases.csv
+func ReadAliases(r io.Reader) (version int, aliases []Alias, err error) {
+	version, err = readTable(r, []string{"alias", "station"}, func(row []string) error {
+		aliases = append(aliases, Alias{Name: row[0], Station: row[1]})
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := validateAliases(aliases); err != nil {
+		return 0, nil, err
+	}
+	return version, aliases, nil
+}
+
+// WriteAliases writes aliases in the format of aliases.csv, sorted by station and alias
+func WriteAliases(w io.Writer, version int, aliases []Alias) error {
+	sorted := append([]Alias(nil), aliases...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Station != sorted[j].Station {
+			return sorted[i].Station < sorted[j].Station
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	fmt.Fprintf(w, "# version: %d\n", version)
+	fmt.Fprintln(w, "# Ridership source names and the GTFS station each one refers to")
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"alias", "station"}); err != nil {
+		return err
+	}
+	for _, a := range sorted {
+		if err := cw.Write([]string{a.Name, a.Station}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SameAliases reports whether two alias lists hold the same mappings, in any order
+func SameAliases(a, b []Alias) bool {
+	set := make(map[Alias]bool, len(a))
+	for _, alias := range a {
+		set[alias] = true
+	}
+	other := make(map[Alias]bool, len(b))
+	for _, alias := range b {
+		if !set[alias] {
+			return false
+		}
+		other[alias] = true
+	}
+	return len(set) == len(other)
+}
+
+// readTable parses a naming file: the version line, optional comments, a header
+// with exactly the given columns, then rows whose fields must all be non-empty
+func readTable(r io.Reader, columns []string, add func(row []string) error) (int, error) {
+	br := bufio.NewReader(r)
+	first, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	version, err := parseVersion(first)
+	if err != nil {
+		return 0, err
+	}
+
+	rest, err := io.ReadAll(br)
+	if err != nil {
+		return 0, err
+	}
+	cr := csv.NewReader(bytes.NewReader(rest))
+	cr.Comment = '#'
+	cr.FieldsPerRecord = len(columns)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return 0, fmt.Errorf("missing header row %q: %w", strings.Join(columns, ","), err)
+	}
+	for i, column := range columns {
+		if strings.TrimSpace(header[i]) != column {
+			return 0, fmt.Errorf("header must be %q, got %q", strings.Join(columns, ","), strings.Join(header, ","))
+		}
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		line, _ := cr.FieldPos(0)
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+			if row[i] == "" {
+				return 0, fmt.Errorf("line %d: %s is empty", line+1, columns[i])
+			}
+		}
+		if err := add(row); err != nil {
+			return 0, fmt.Errorf("line %d: %w", line+1, err)
+		}
+	}
+
+	return version, nil
+}
+
+// parseVersion reads a "# version: N" line
+func parseVersion(line string) (int, error) {
+	value, ok := strings.CutPrefix(strings.TrimSpace(line), "# version:")
+	if !ok {
+		return 0, fmt.Errorf("first line must be \"# version: N\"")
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid version %q", strings.TrimSpace(value))
+	}
+	return version, nil
+}
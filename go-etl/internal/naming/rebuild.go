@@ -0,0 +1,106 @@
+package naming
+
+import (
+	"sort"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+// AliasChange is a stored alias whose normalized form differs under the current rules
+type AliasChange struct {
+	db.StationAliasRecord
+	NewNormalized string
+}
+
+// AliasCollision is a normalized alias that refers to more than one station
+type AliasCollision struct {
+	Normalized string
+	Aliases    []db.StationAliasRecord // Sorted by station name, then alias
+	New        bool                    // Only collides with the recomputed values
+}
+
+// RebuildPlan is what recomputing every stored alias's normalized form would change
+type RebuildPlan struct {
+	Changes    []AliasChange
+	Unchanged  int
+	Collisions []AliasCollision // After the changes are applied
+}
+
+// PlanRebuild recomputes the normalized form of each stored alias with n and finds
+// the collisions the results would leave
+func PlanRebuild(records []db.StationAliasRecord, n *normalize.Normalizer) RebuildPlan {
+	var plan RebuildPlan
+	rebuilt := make([]db.StationAliasRecord, len(records))
+	for i, r := range records {
+		rebuilt[i] = r
+		normalized := n.Normalize(r.AliasName)
+		if normalized == r.Normalized {
+			plan.Unchanged++
+			continue
+		}
+		plan.Changes = append(plan.Changes, AliasChange{StationAliasRecord: r, NewNormalized: normalized})
+		rebuilt[i].Normalized = normalized
+	}
+
+	before := make(map[string]bool)
+	for _, c := range FindCollisions(records) {
+		before[collisionKey(c)] = true
+	}
+	plan.Collisions = FindCollisions(rebuilt)
+	for i := range plan.Collisions {
+		plan.Collisions[i].New = !before[collisionKey(plan.Collisions[i])]
+	}
+	return plan
+}
+
+// FindCollisions groups aliases by normalized form and returns the forms shared by
+// more than one station, in order of normalized form
+func FindCollisions(records []db.StationAliasRecord) []AliasCollision {
+	byNormalized := make(map[string][]db.StationAliasRecord)
+	for _, r := range records {
+		byNormalized[r.Normalized] = append(byNormalized[r.Normalized], r)
+	}
+
+	var collisions []AliasCollision
+	for normalized, aliases := range byNormalized {
+		stations := make(map[string]bool)
+		for _, a := range aliases {
+			stations[a.StationID] = true
+		}
+		if len(stations) < 2 {
+			continue
+		}
+		sort.Slice(aliases, func(i, j int) bool {
+			if aliases[i].StationName != aliases[j].StationName {
+				return aliases[i].StationName < aliases[j].StationName
+			}
+			return aliases[i].AliasName < aliases[j].AliasName
+		})
+		collisions = append(collisions, AliasCollision{Normalized: normalized, Aliases: aliases})
+	}
+
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Normalized < collisions[j].Normalized
+	})
+	return collisions
+}
+
+// collisionKey identifies a collision by its normalized form and stations, so the
+// same stations colliding before and after a rebuild isn't reported as new
+func collisionKey(c AliasCollision) string {
+	ids := make([]string, 0, len(c.Aliases))
+	seen := make(map[string]bool)
+	for _, a := range c.Aliases {
+		if !seen[a.StationID] {
+			seen[a.StationID] = true
+			ids = append(ids, a.StationID)
+		}
+	}
+	sort.Strings(ids)
+	key := c.Normalized
+	for _, id := range ids {
+		key += "\x00" + id
+	}
+	return key
+}
@@ -0,0 +1,169 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/nate/ghost-stops/go-etl/internal/db"
+	"github.com/nate/ghost-stops/go-etl/internal/normalize"
+)
+
+func aliasRecord(id, stationID, stationName, aliasName, normalized string) db.StationAliasRecord {
+	return db.StationAliasRecord{ID: id, StationID: stationID, StationName: stationName, AliasName: aliasName, Normalized: normalized}
+}
+
+var (
+	halstedOrange = aliasRecord("a1", "s1", "Halsted (Orange)", "Halsted", "halsted")
+	halstedGreen  = aliasRecord("a2", "s2", "Halsted (Green)", "Halsted", "halsted")
+	westernBlue   = aliasRecord("a3", "s3", "Western (Blue)", "Western Station", "western station")
+	westernBrown  = aliasRecord("a4", "s4", "Western (Brown)", "Western", "western")
+	clarkLake     = aliasRecord("a5", "s5", "Clark/Lake", "Clark/Lake", "clark/lake")
+	clarkLake2    = aliasRecord("a6", "s5", "Clark/Lake", "clark lake", "clark lake")
+)
+
+// collisionSummary is a collision's normalized form, alias IDs in order and New
+type collisionSummary struct {
+	normalized string
+	ids        []string
+	new        bool
+}
+
+func checkCollisions(t *testing.T, got []AliasCollision, want []collisionSummary) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d collisions %+v, want %+v", len(got), got, want)
+	}
+	for i, w := range want {
+		g := got[i]
+		ids := make([]string, len(g.Aliases))
+		for j, a := range g.Aliases {
+			ids[j] = a.ID
+		}
+		if g.Normalized != w.normalized || g.New != w.new || len(ids) != len(w.ids) {
+			t.Errorf("collision %d: got %s %v new=%v, want %s %v new=%v", i, g.Normalized, ids, g.New, w.normalized, w.ids, w.new)
+			continue
+		}
+		for j := range ids {
+			if ids[j] != w.ids[j] {
+				t.Errorf("collision %d: got aliases %v, want %v", i, ids, w.ids)
+				break
+			}
+		}
+	}
+}
+
+func TestFindCollisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []db.StationAliasRecord
+		want    []collisionSummary
+	}{
+		{"none", []db.StationAliasRecord{halstedOrange, westernBrown, clarkLake}, nil},
+		{
+			"one station's aliases sharing a form",
+			[]db.StationAliasRecord{clarkLake2, aliasRecord("a7", "s5", "Clark/Lake", "Clark Lake", "clark lake")},
+			nil,
+		},
+		{
+			// Ordered by station name
+			"two stations",
+			[]db.StationAliasRecord{halstedOrange, halstedGreen, westernBrown},
+			[]collisionSummary{{"halsted", []string{"a2", "a1"}, false}},
+		},
+		{
+			// Ordered by normalized form, then by station name and alias within each
+			"several",
+			[]db.StationAliasRecord{
+				aliasRecord("a8", "s4", "Western (Brown)", "Western Brown", "western"),
+				westernBrown,
+				halstedOrange,
+				aliasRecord("a9", "s3", "Western (Blue)", "Western", "western"),
+				halstedGreen,
+			},
+			[]collisionSummary{
+				{"halsted", []string{"a2", "a1"}, false},
+				{"western", []string{"a9", "a4", "a8"}, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkCollisions(t, FindCollisions(tt.records), tt.want)
+		})
+	}
+}
+
+func TestPlanRebuild(t *testing.T) {
+	n := normalize.Default()
+
+	tests := []struct {
+		name       string
+		records    []db.StationAliasRecord
+		changes    map[string]string // Alias ID -> new normalized form
+		unchanged  int
+		collisions []collisionSummary
+	}{
+		{
+			name:      "nothing to change",
+			records:   []db.StationAliasRecord{halstedOrange, westernBrown, clarkLake2},
+			unchanged: 3,
+		},
+		{
+			name:      "stale forms recomputed",
+			records:   []db.StationAliasRecord{clarkLake, clarkLake2},
+			changes:   map[string]string{"a5": "clark lake"},
+			unchanged: 1,
+		},
+		{
+			// Dropping the stop word makes both Westerns "western"
+			name:       "new collision",
+			records:    []db.StationAliasRecord{westernBlue, westernBrown},
+			changes:    map[string]string{"a3": "western"},
+			unchanged:  1,
+			collisions: []collisionSummary{{"western", []string{"a3", "a4"}, true}},
+		},
+		{
+			name:       "existing collision isn't new",
+			records:    []db.StationAliasRecord{halstedOrange, halstedGreen},
+			unchanged:  2,
+			collisions: []collisionSummary{{"halsted", []string{"a2", "a1"}, false}},
+		},
+		{
+			// The same form colliding between more stations than before
+			name: "existing collision gains a station",
+			records: []db.StationAliasRecord{
+				halstedOrange,
+				halstedGreen,
+				aliasRecord("a7", "s6", "Halsted (UIC)", "Halsted Station", "halsted station"),
+			},
+			changes:    map[string]string{"a7": "halsted"},
+			unchanged:  2,
+			collisions: []collisionSummary{{"halsted", []string{"a2", "a1", "a7"}, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanRebuild(tt.records, n)
+			if plan.Unchanged != tt.unchanged {
+				t.Errorf("Unchanged = %d, want %d", plan.Unchanged, tt.unchanged)
+			}
+			if len(plan.Changes) != len(tt.changes) {
+				t.Errorf("got changes %+v, want %v", plan.Changes, tt.changes)
+			}
+			for _, c := range plan.Changes {
+				if want, ok := tt.changes[c.ID]; !ok || c.NewNormalized != want {
+					t.Errorf("alias %s: %q -> %q, want %q", c.ID, c.Normalized, c.NewNormalized, want)
+				}
+			}
+			checkCollisions(t, plan.Collisions, tt.collisions)
+		})
+	}
+
+	// The records passed in are left as they were
+	records := []db.StationAliasRecord{clarkLake}
+	PlanRebuild(records, n)
+	if records[0].Normalized != "clark/lake" {
+		t.Errorf("PlanRebuild modified its input: %+v", records[0])
+	}
+}
@@ -0,0 +1,41 @@
+package normalize
+
+import "strings"
+
+// diacritics maps accented Latin letters to their unaccented forms
+var diacritics = func() *strings.Replacer {
+	groups := []struct{ base, accented string }{
+		{"A", "ÀÁÂÃÄÅĀĂĄ"}, {"a", "àáâãäåāăą"},
+		{"C", "ÇĆĈĊČ"}, {"c", "çćĉċč"},
+		{"D", "ĎĐ"}, {"d", "ďđ"},
+		{"E", "ÈÉÊËĒĔĖĘĚ"}, {"e", "èéêëēĕėęě"},
+		{"G", "ĜĞĠĢ"}, {"g", "ĝğġģ"},
+		{"H", "ĤĦ"}, {"h", "ĥħ"},
+		{"I", "ÌÍÎÏĨĪĬĮİ"}, {"i", "ìíîïĩīĭįı"},
+		{"J", "Ĵ"}, {"j", "ĵ"},
+		{"K", "Ķ"}, {"k", "ķ"},
+		{"L", "ĹĻĽĿŁ"}, {"l", "ĺļľŀł"},
+		{"N", "ÑŃŅŇ"}, {"n", "ñńņň"},
+		{"O", "ÒÓÔÕÖØŌŎŐ"}, {"o", "òóôõöøōŏő"},
+		{"R", "ŔŖŘ"}, {"r", "ŕŗř"},
+		{"S", "ŚŜŞŠ"}, {"s", "śŝşš"},
+		{"T", "ŢŤŦ"}, {"t", "ţťŧ"},
+		{"U", "ÙÚÛÜŨŪŬŮŰŲ"}, {"u", "ùúûüũūŭůűų"},
+		{"W", "Ŵ"}, {"w", "ŵ"},
+		{"Y", "ÝŶŸ"}, {"y", "ýÿŷ"},
+		{"Z", "ŹŻŽ"}, {"z", "źżž"},
+	}
+	var pairs []string
+	for _, g := range groups {
+		for _, r := range g.accented {
+			pairs = append(pairs, string(r), g.base)
+		}
+	}
+	pairs = append(pairs, "Æ", "AE", "æ", "ae", "Œ", "OE", "œ", "oe", "ß", "ss")
+	return strings.NewReplacer(pairs...)
+}()
+
+// foldDiacritics replaces accented Latin letters with their unaccented forms
+func foldDiacritics(s string) string {
+	return diacritics.Replace(s)
+}
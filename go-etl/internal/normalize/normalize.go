@@ -0,0 +1,184 @@
+// Package normalize turns station names into the keys they are matched on, by
+// applying an ordered list of rules. Each city chooses its rules, abbreviations
+// and stop words (see the naming package); a Normalizer is compiled once from
+// that configuration and shared by alias storage and station matching.
+package normalize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Rule names, as listed in a city's normalization.csv
+const (
+	FoldDiacritics = "fold_diacritics" // "Café" -> "Cafe"
+	Lowercase      = "lowercase"       // "Clark" -> "clark"
+	Symbols        = "symbols"         // "&" -> "and"
+	Separators     = "separators"      // "/" and "-" -> " "
+	Punctuation    = "punctuation"     // Drop anything but letters, digits and spaces
+	Abbreviations  = "abbreviations"   // "st" -> "street", per the city's abbreviations
+	Ordinals       = "ordinals"        // "35th" -> "35"
+	StopWords      = "stop_words"      // Drop the city's stop words, e.g. "station"
+	Spaces         = "spaces"          // Collapse and trim whitespace
+)
+
+// DefaultRules is the rule order used by cities that don't configure their own
+var DefaultRules = []string{Lowercase, Symbols, Separators, Punctuation, StopWords, Spaces}
+
+// Config selects a normalizer's rules and the data they use
+type Config struct {
+	Rules         []string          // Rule names, applied in order
+	Abbreviations map[string]string // Lowercase word -> expansion, for the abbreviations rule
+	StopWords     []string          // Lowercase words dropped by the stop_words rule
+}
+
+// DefaultConfig is used by cities without normalization files
+func DefaultConfig() Config {
+	return Config{Rules: DefaultRules, StopWords: []string{"station"}}
+}
+
+// Normalizer applies a compiled list of rules to station names
+type Normalizer struct {
+	rules []rule
+}
+
+type rule struct {
+	name  string
+	apply func(string) string
+}
+
+// Step is the result of one rule, as reported by Trace
+type Step struct {
+	Rule   string
+	Output string
+}
+
+var (
+	punctuation = regexp.MustCompile(`[^\w\s]`)
+	ordinals    = regexp.MustCompile(`(?i)\b(\d+)(st|nd|rd|th)\b`)
+	whitespace  = regexp.MustCompile(`\s+`)
+	separators  = strings.NewReplacer("/", " ", "-", " ")
+
+	defaultOnce       sync.Once
+	defaultNormalizer *Normalizer
+)
+
+// Default returns the normalizer for DefaultConfig
+func Default() *Normalizer {
+	defaultOnce.Do(func() {
+		n, err := New(DefaultConfig())
+		if err != nil {
+			panic(fmt.Sprintf("normalize: invalid default config: %v", err))
+		}
+		defaultNormalizer = n
+	})
+	return defaultNormalizer
+}
+
+// New compiles a configuration, rejecting unknown or repeated rules
+func New(cfg Config) (*Normalizer, error) {
+	n := &Normalizer{}
+	seen := make(map[string]bool)
+	for _, name := range cfg.Rules {
+		if seen[name] {
+			return nil, fmt.Errorf("rule %q listed twice", name)
+		}
+		seen[name] = true
+
+		var apply func(string) string
+		switch name {
+		case FoldDiacritics:
+			apply = foldDiacritics
+		case Lowercase:
+			apply = strings.ToLower
+		case Symbols:
+			apply = func(s string) string { return strings.ReplaceAll(s, "&", "and") }
+		case Separators:
+			apply = separators.Replace
+		case Punctuation:
+			apply = func(s string) string { return punctuation.ReplaceAllString(s, "") }
+		case Abbreviations:
+			expansions := make(map[string]string, len(cfg.Abbreviations))
+			for abbr, expansion := range cfg.Abbreviations {
+				expansions[strings.ToLower(abbr)] = expansion
+			}
+			apply = func(s string) string {
+				return mapWords(s, func(w string) string {
+					if expansion, ok := expansions[w]; ok {
+						return expansion
+					}
+					return w
+				})
+			}
+		case Ordinals:
+			apply = func(s string) string { return ordinals.ReplaceAllString(s, "$1") }
+		case StopWords:
+			stop := make(map[string]bool, len(cfg.StopWords))
+			for _, w := range cfg.StopWords {
+				stop[strings.ToLower(w)] = true
+			}
+			apply = func(s string) string {
+				return mapWords(s, func(w string) string {
+					if stop[w] {
+						return ""
+					}
+					return w
+				})
+			}
+		case Spaces:
+			apply = func(s string) string { return strings.TrimSpace(whitespace.ReplaceAllString(s, " ")) }
+		default:
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+		n.rules = append(n.rules, rule{name: name, apply: apply})
+	}
+
+	if len(cfg.Abbreviations) > 0 && !seen[Abbreviations] {
+		return nil, fmt.Errorf("abbreviations are listed but the %s rule is not", Abbreviations)
+	}
+	if len(cfg.StopWords) > 0 && !seen[StopWords] {
+		return nil, fmt.Errorf("stop words are listed but the %s rule is not", StopWords)
+	}
+	return n, nil
+}
+
+// Normalize applies every rule to name in order
+func (n *Normalizer) Normalize(name string) string {
+	for _, r := range n.rules {
+		name = r.apply(name)
+	}
+	return name
+}
+
+// Trace applies every rule to name in order, recording the output of each
+func (n *Normalizer) Trace(name string) []Step {
+	steps := make([]Step, 0, len(n.rules))
+	for _, r := range n.rules {
+		name = r.apply(name)
+		steps = append(steps, Step{Rule: r.name, Output: name})
+	}
+	return steps
+}
+
+// Rules lists the normalizer's rule names in the order they are applied
+func (n *Normalizer) Rules() []string {
+	names := make([]string, len(n.rules))
+	for i, r := range n.rules {
+		names[i] = r.name
+	}
+	return names
+}
+
+// mapWords replaces each space-separated word of s, dropping words mapped to ""
+func mapWords(s string, f func(string) string) string {
+	words := strings.Fields(s)
+	kept := words[:0]
+	for _, w := range words {
+		if w = f(w); w != "" {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
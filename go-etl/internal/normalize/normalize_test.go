@@ -0,0 +1,116 @@
+package normalize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string // Empty when the config is valid
+	}{
+		{"default", DefaultConfig(), ""},
+		{"no rules", Config{}, ""},
+		{"every rule", Config{
+			Rules:         []string{FoldDiacritics, Lowercase, Symbols, Separators, Punctuation, Abbreviations, Ordinals, StopWords, Spaces},
+			Abbreviations: map[string]string{"st": "street"},
+			StopWords:     []string{"station"},
+		}, ""},
+		{"unknown rule", Config{Rules: []string{Lowercase, "stemming"}}, `unknown rule "stemming"`},
+		{"repeated rule", Config{Rules: []string{Lowercase, Spaces, Lowercase}}, `rule "lowercase" listed twice`},
+		{"abbreviations without the rule", Config{
+			Rules:         []string{Lowercase},
+			Abbreviations: map[string]string{"st": "street"},
+		}, "abbreviations are listed"},
+		{"stop words without the rule", Config{
+			Rules:     []string{Lowercase},
+			StopWords: []string{"station"},
+		}, "stop words are listed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := New(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("New: %v", err)
+				}
+				if got := strings.Join(n.Rules(), ","); got != strings.Join(tt.cfg.Rules, ",") {
+					t.Errorf("Rules() = %s, want %s", got, strings.Join(tt.cfg.Rules, ","))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New: got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	abbreviations := map[string]string{"st": "street", "ave": "avenue"}
+	chicago := Config{
+		Rules:         []string{FoldDiacritics, Lowercase, Symbols, Separators, Punctuation, Abbreviations, Ordinals, StopWords, Spaces},
+		Abbreviations: abbreviations,
+		StopWords:     []string{"station"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		in   string
+		want string
+	}{
+		{"default", DefaultConfig(), "Clark/Lake Station", "clark lake"},
+		{"default, symbols", DefaultConfig(), "Sox-35th & Dan Ryan", "sox 35th and dan ryan"},
+		{"default, punctuation", DefaultConfig(), "Addison (Blue Line)", "addison blue line"},
+		{"default, spaces", DefaultConfig(), "  Harlem   -  Lake  ", "harlem lake"},
+		{"every rule", chicago, "Cermak-Chinatown St. Station", "cermak chinatown street"},
+		{"every rule, ordinals", chicago, "35th/Archer", "35 archer"},
+		{"every rule, diacritics", chicago, "Café Ñandú Ave", "cafe nandu avenue"},
+		{"no rules", Config{}, "Clark/Lake", "Clark/Lake"},
+
+		// Rule order matters: each rule sees the previous one's output
+		{"separators before punctuation", Config{Rules: []string{Separators, Punctuation}}, "Clark/Lake", "Clark Lake"},
+		{"punctuation before separators", Config{Rules: []string{Punctuation, Separators}}, "Clark/Lake", "ClarkLake"},
+		{"abbreviations after lowercase", Config{Rules: []string{Lowercase, Abbreviations}, Abbreviations: abbreviations}, "Halsted St", "halsted street"},
+		{"abbreviations before lowercase", Config{Rules: []string{Abbreviations, Lowercase}, Abbreviations: abbreviations}, "Halsted St", "halsted st"},
+		{"diacritics folded before punctuation", Config{Rules: []string{FoldDiacritics, Punctuation}}, "Café", "Cafe"},
+		{"diacritics dropped by punctuation first", Config{Rules: []string{Punctuation, FoldDiacritics}}, "Café", "Caf"},
+		{"stop words before lowercase", Config{Rules: []string{StopWords, Lowercase}, StopWords: []string{"station"}}, "Howard Station", "howard station"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := n.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrace(t *testing.T) {
+	steps := Default().Trace("Clark/Lake Station")
+	want := []Step{
+		{Lowercase, "clark/lake station"},
+		{Symbols, "clark/lake station"},
+		{Separators, "clark lake station"},
+		{Punctuation, "clark lake station"},
+		{StopWords, "clark lake"},
+		{Spaces, "clark lake"},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %+v, want %+v", steps, want)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("step %d: got %+v, want %+v", i, steps[i], want[i])
+		}
+	}
+}
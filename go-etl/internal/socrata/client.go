@@ -0,0 +1,206 @@
+package socrata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AppTokenEnv is the environment variable holding the Socrata app token
+const AppTokenEnv = "CHICAGO_DATA_APP_TOKEN"
+
+const (
+	defaultMaxRetries = 6
+	defaultBaseDelay  = time.Second
+	maxDelay          = time.Minute
+)
+
+// Dataset identifies a Socrata dataset by portal domain and 4x4 ID
+type Dataset struct {
+	Domain string // e.g. "data.cityofchicago.org"
+	ID     string // e.g. "5neh-572f"
+}
+
+// URL returns the dataset's JSON resource endpoint
+func (d Dataset) URL() string {
+	return fmt.Sprintf("https://%s/resource/%s.json", d.Domain, d.ID)
+}
+
+// AppToken returns the app token from the environment, or "" when unset.
+// Requests work without one but are throttled more aggressively.
+func AppToken() string {
+	return os.Getenv(AppTokenEnv)
+}
+
+// Client fetches from Socrata datasets, retrying rate limits, server errors and
+// network failures with exponential backoff. One Client should be shared.
+type Client struct {
+	http  *http.Client
+	token string
+
+	BaseURL    string // Overrides each dataset's https://<domain>, e.g. for a local test server
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// NewClient returns a client that sends token (if not empty) with each request
+func NewClient(token string) *Client {
+	return &Client{
+		http:       &http.Client{Timeout: 60 * time.Second},
+		token:      token,
+		MaxRetries: defaultMaxRetries,
+		BaseDelay:  defaultBaseDelay,
+	}
+}
+
+// StatusError is a non-200 response from Socrata
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Socrata API returned non-200 status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether a request that failed with this status is worth retrying
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Get runs a query against a dataset and decodes the JSON rows into v, which
+// should be a pointer to a slice of structs tagged with the dataset's column names
+func (c *Client) Get(ds Dataset, q *Query, v interface{}) error {
+	fullURL := ds.URL()
+	if c.BaseURL != "" {
+		fullURL = fmt.Sprintf("%s/resource/%s.json", strings.TrimRight(c.BaseURL, "/"), ds.ID)
+	}
+	if q != nil {
+		if params := q.String(); params != "" {
+			fullURL += "?" + params
+		}
+	}
+	log.Printf("Fetching page: %s", fullURL)
+	return c.getJSON(fullURL, v)
+}
+
+// EachPage runs a query page by page starting at offset, decoding each page into
+// T and passing it to fn with the offset of the next page. It stops after a short
+// page, or when fn returns an error. The query should have a stable $order.
+func EachPage[T any](c *Client, ds Dataset, q *Query, pageSize, offset int, fn func(page []T, next int) error) error {
+	for {
+		var page []T
+		if err := c.Get(ds, q.Clone().Limit(pageSize).Offset(offset), &page); err != nil {
+			return fmt.Errorf("failed to fetch %s at offset %d: %w", ds.ID, offset, err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		offset += len(page)
+		if err := fn(page, offset); err != nil {
+			return err
+		}
+
+		if len(page) < pageSize {
+			return nil
+		}
+	}
+}
+
+// getJSON fetches url and decodes the JSON response into v, retrying transient failures
+func (c *Client) getJSON(url string, v interface{}) error {
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff(attempt)
+			if se, ok := lastErr.(*StatusError); ok && se.RetryAfter > 0 {
+				delay = se.RetryAfter
+			}
+			log.Printf("Warning: %v; retrying in %s (attempt %d of %d)", lastErr, delay, attempt, c.MaxRetries)
+			time.Sleep(delay)
+		}
+
+		body, err := c.get(url)
+		if err != nil {
+			lastErr = err
+			if se, ok := err.(*StatusError); ok && !se.Retryable() {
+				return err
+			}
+			continue
+		}
+
+		if err := json.Unmarshal(body, v); err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("giving up after %d retries: %w", c.MaxRetries, lastErr)
+}
+
+// get performs a single request and returns the response body
+func (c *Client) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("X-App-Token", c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	return body, nil
+}
+
+// backoff returns the delay before a retry: doubling from BaseDelay, capped at
+// maxDelay, with up to 50% jitter so parallel jobs don't retry in lockstep
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
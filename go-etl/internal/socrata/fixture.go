@@ -0,0 +1,112 @@
+package socrata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FixtureMode selects whether a fixture directory is read from or written to
+type FixtureMode int
+
+const (
+	// Replay serves responses from recorded files and never touches the network
+	Replay FixtureMode = iota
+	// Record performs real requests and saves each successful response
+	Record
+)
+
+// UseFixtures routes the client's requests through a fixture directory. In
+// Replay mode a request without a recorded response fails with a 404.
+func (c *Client) UseFixtures(dir string, mode FixtureMode) error {
+	switch mode {
+	case Record:
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create fixture directory: %w", err)
+		}
+	case Replay:
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("fixture directory %s not found", dir)
+		}
+	}
+
+	next := c.http.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.http.Transport = &fixtureTransport{dir: dir, mode: mode, next: next}
+	return nil
+}
+
+// FixturePath returns the file a request URL is recorded to. Only the path and
+// query identify a request, so fixtures replay against any --base-url.
+func FixturePath(dir string, u *url.URL) string {
+	key := u.Path + "?" + u.Query().Encode()
+	sum := sha256.Sum256([]byte(key))
+	name := strings.TrimSuffix(path.Base(u.Path), ".json")
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", name, hex.EncodeToString(sum[:6])))
+}
+
+// fixtureTransport records or replays response bodies as JSON files
+type fixtureTransport struct {
+	dir  string
+	mode FixtureMode
+	next http.RoundTripper
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	file := FixturePath(t.dir, req.URL)
+
+	if t.mode == Replay {
+		body, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			return fixtureResponse(req, http.StatusNotFound,
+				[]byte(fmt.Sprintf("no fixture %s recorded for %s", file, req.URL))), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		return fixtureResponse(req, http.StatusOK, body), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, body, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+	log.Printf("Recorded %s", file)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func fixtureResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,120 @@
+package socrata
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FloatingTimestampFormat is how SoQL writes floating_timestamp values
+const FloatingTimestampFormat = "2006-01-02T15:04:05.000"
+
+// Query builds the SoQL parameters for a dataset request. The zero value selects
+// every column of every row; each method returns the query for chaining.
+type Query struct {
+	selects []string
+	wheres  []string
+	orders  []string
+	groups  []string
+	limit   int
+	offset  int
+}
+
+// NewQuery returns an empty query
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Select adds columns or expressions to $select
+func (q *Query) Select(columns ...string) *Query {
+	q.selects = append(q.selects, columns...)
+	return q
+}
+
+// Where adds a condition to $where; multiple conditions are ANDed together
+func (q *Query) Where(condition string) *Query {
+	q.wheres = append(q.wheres, condition)
+	return q
+}
+
+// OrderAsc adds an ascending column to $order
+func (q *Query) OrderAsc(column string) *Query {
+	q.orders = append(q.orders, column+" ASC")
+	return q
+}
+
+// OrderDesc adds a descending column to $order
+func (q *Query) OrderDesc(column string) *Query {
+	q.orders = append(q.orders, column+" DESC")
+	return q
+}
+
+// Group adds columns to $group
+func (q *Query) Group(columns ...string) *Query {
+	q.groups = append(q.groups, columns...)
+	return q
+}
+
+// Limit sets $limit; 0 leaves the API default
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Offset sets $offset
+func (q *Query) Offset(n int) *Query {
+	q.offset = n
+	return q
+}
+
+// Clone returns a copy that can be changed without affecting q
+func (q *Query) Clone() *Query {
+	c := *q
+	c.selects = append([]string(nil), q.selects...)
+	c.wheres = append([]string(nil), q.wheres...)
+	c.orders = append([]string(nil), q.orders...)
+	c.groups = append([]string(nil), q.groups...)
+	return &c
+}
+
+// Values returns the query as URL parameters
+func (q *Query) Values() url.Values {
+	params := url.Values{}
+	if len(q.selects) > 0 {
+		params.Set("$select", strings.Join(q.selects, ", "))
+	}
+	if len(q.wheres) == 1 {
+		params.Set("$where", q.wheres[0])
+	} else if len(q.wheres) > 1 {
+		params.Set("$where", "("+strings.Join(q.wheres, ") AND (")+")")
+	}
+	if len(q.orders) > 0 {
+		params.Set("$order", strings.Join(q.orders, ", "))
+	}
+	if len(q.groups) > 0 {
+		params.Set("$group", strings.Join(q.groups, ", "))
+	}
+	if q.limit > 0 {
+		params.Set("$limit", strconv.Itoa(q.limit))
+	}
+	if q.offset > 0 {
+		params.Set("$offset", strconv.Itoa(q.offset))
+	}
+	return params
+}
+
+// String returns the encoded query string
+func (q *Query) String() string {
+	return q.Values().Encode()
+}
+
+// Quote returns s as a SoQL string literal
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Timestamp returns t as a SoQL floating_timestamp literal
+func Timestamp(t time.Time) string {
+	return Quote(t.Format(FloatingTimestampFormat))
+}
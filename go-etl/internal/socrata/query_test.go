@@ -0,0 +1,112 @@
+package socrata
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestQueryValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  map[string]string // Parameters; any others must be absent
+	}{
+		{"empty", NewQuery(), map[string]string{}},
+		{
+			"select and order",
+			NewQuery().Select("station_id", "stationname").Select("rides").OrderAsc("date").OrderDesc("rides"),
+			map[string]string{"$select": "station_id, stationname, rides", "$order": "date ASC, rides DESC"},
+		},
+		{"one condition", NewQuery().Where("rides > 0"), map[string]string{"$where": "rides > 0"}},
+		{
+			"conditions are ANDed",
+			NewQuery().Where("date >= '2024-06-01'").Where("rides > 0 OR daytype = 'U'"),
+			map[string]string{"$where": "(date >= '2024-06-01') AND (rides > 0 OR daytype = 'U')"},
+		},
+		{
+			"group, limit and offset",
+			NewQuery().Select("station_id", "sum(rides)").Group("station_id").Limit(1000).Offset(2000),
+			map[string]string{"$select": "station_id, sum(rides)", "$group": "station_id", "$limit": "1000", "$offset": "2000"},
+		},
+		{"zero limit and offset are omitted", NewQuery().Limit(0).Offset(0), map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.query.Values()
+			if len(got) != len(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got.Get(k) != v {
+					t.Errorf("%s = %q, want %q", k, got.Get(k), v)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	q := NewQuery().
+		Where("date >= " + Quote("2024-06-01")).
+		Where("stationname = " + Quote("O'Hare")).
+		OrderAsc("date").
+		Limit(50000)
+
+	// Parameters are sorted by name and percent-encoded
+	want := "%24limit=50000&%24order=date+ASC" +
+		"&%24where=%28date+%3E%3D+%272024-06-01%27%29+AND+%28stationname+%3D+%27O%27%27Hare%27%29"
+	if got := q.String(); got != want {
+		t.Errorf("String() =\n  %s\nwant\n  %s", got, want)
+	}
+
+	// And decode back to the same conditions
+	values, err := url.ParseQuery(q.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Get("$where"); got != "(date >= '2024-06-01') AND (stationname = 'O''Hare')" {
+		t.Errorf("decoded $where = %q", got)
+	}
+}
+
+func TestQueryClone(t *testing.T) {
+	base := NewQuery().Select("station_id").Where("rides > 0").OrderAsc("date").Group("station_id").Limit(10)
+	page := base.Clone().Where("date > '2024-01-01'").Offset(10)
+	base.Select("rides")
+
+	if got := base.Values().Get("$where"); got != "rides > 0" {
+		t.Errorf("base $where changed by the clone: %q", got)
+	}
+	if base.Values().Get("$offset") != "" {
+		t.Error("base $offset set by the clone")
+	}
+	if got := page.Values().Get("$select"); got != "station_id" {
+		t.Errorf("clone $select changed by the base: %q", got)
+	}
+	if got := page.Values().Get("$where"); got != "(rides > 0) AND (date > '2024-01-01')" {
+		t.Errorf("clone $where = %q", got)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", "''"},
+		{"Clark/Lake", "'Clark/Lake'"},
+		{"O'Hare", "'O''Hare'"},
+		{"''", "''''''"},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 8, 30, 5, 250_000_000, time.UTC)
+	if got := Timestamp(ts); got != "'2024-06-01T08:30:05.250'" {
+		t.Errorf("Timestamp = %s", got)
+	}
+}
@@ -0,0 +1,14 @@
+-- CreateTable
+CREATE TABLE "Route" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "cityId" TEXT NOT NULL,
+    "externalId" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "color" TEXT,
+    "textColor" TEXT,
+    "routeType" INTEGER NOT NULL,
+    CONSTRAINT "Route_cityId_fkey" FOREIGN KEY ("cityId") REFERENCES "City" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "Route_cityId_externalId_key" ON "Route"("cityId", "externalId");
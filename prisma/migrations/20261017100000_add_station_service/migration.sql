@@ -0,0 +1,18 @@
+-- AlterTable
+ALTER TABLE "StationMetrics" ADD COLUMN "ridersPerTrain" REAL;
+
+-- CreateTable
+CREATE TABLE "StationService" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "stationId" TEXT NOT NULL,
+    "weekdayTrips" REAL NOT NULL,
+    "saturdayTrips" REAL NOT NULL,
+    "sundayTrips" REAL NOT NULL,
+    "feedStartDate" TEXT NOT NULL,
+    "feedEndDate" TEXT NOT NULL,
+    "lastUpdated" DATETIME NOT NULL,
+    CONSTRAINT "StationService_stationId_fkey" FOREIGN KEY ("stationId") REFERENCES "Station" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "StationService_stationId_key" ON "StationService"("stationId");
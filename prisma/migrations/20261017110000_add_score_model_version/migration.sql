@@ -0,0 +1,2 @@
+-- AlterTable
+ALTER TABLE "StationMetrics" ADD COLUMN "modelVersion" TEXT;
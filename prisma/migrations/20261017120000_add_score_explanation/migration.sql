@@ -0,0 +1,19 @@
+-- CreateTable
+CREATE TABLE "GhostScoreExplanation" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "stationId" TEXT NOT NULL,
+    "modelVersion" TEXT NOT NULL,
+    "ghostScore" INTEGER NOT NULL,
+    "rank" INTEGER NOT NULL,
+    "peerGroupSize" INTEGER NOT NULL,
+    "windowEnd" TEXT NOT NULL,
+    "window30Start" TEXT NOT NULL,
+    "window90Start" TEXT NOT NULL,
+    "inputs" TEXT NOT NULL,
+    "factors" TEXT NOT NULL,
+    "computedAt" DATETIME NOT NULL,
+    CONSTRAINT "GhostScoreExplanation_stationId_fkey" FOREIGN KEY ("stationId") REFERENCES "Station" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "GhostScoreExplanation_stationId_key" ON "GhostScoreExplanation"("stationId");
@@ -0,0 +1,20 @@
+-- CreateTable
+CREATE TABLE "StationMetricsHistory" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "stationId" TEXT NOT NULL,
+    "snapshotDate" TEXT NOT NULL,
+    "ghostScore" INTEGER NOT NULL,
+    "rolling30dAvg" REAL NOT NULL,
+    "rolling90dAvg" REAL NOT NULL,
+    "lastDayEntries" INTEGER NOT NULL,
+    "dataStatus" TEXT NOT NULL,
+    "modelVersion" TEXT NOT NULL,
+    "computedAt" DATETIME NOT NULL,
+    CONSTRAINT "StationMetricsHistory_stationId_fkey" FOREIGN KEY ("stationId") REFERENCES "Station" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "StationMetricsHistory_stationId_snapshotDate_key" ON "StationMetricsHistory"("stationId", "snapshotDate");
+
+-- CreateIndex
+CREATE INDEX "StationMetricsHistory_snapshotDate_idx" ON "StationMetricsHistory"("snapshotDate");
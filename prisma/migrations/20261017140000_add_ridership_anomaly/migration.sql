@@ -0,0 +1,19 @@
+-- CreateTable
+CREATE TABLE "RidershipAnomaly" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "stationId" TEXT NOT NULL,
+    "serviceDate" TEXT NOT NULL,
+    "kind" TEXT NOT NULL,
+    "severity" TEXT NOT NULL,
+    "entries" INTEGER NOT NULL,
+    "expected" REAL NOT NULL,
+    "score" REAL NOT NULL,
+    "detectedAt" DATETIME NOT NULL,
+    CONSTRAINT "RidershipAnomaly_stationId_fkey" FOREIGN KEY ("stationId") REFERENCES "Station" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "RidershipAnomaly_stationId_serviceDate_key" ON "RidershipAnomaly"("stationId", "serviceDate");
+
+-- CreateIndex
+CREATE INDEX "RidershipAnomaly_serviceDate_idx" ON "RidershipAnomaly"("serviceDate");
@@ -0,0 +1,6 @@
+-- AlterTable
+ALTER TABLE "StationMetrics" ADD COLUMN "slope90d" REAL;
+ALTER TABLE "StationMetrics" ADD COLUMN "slope365d" REAL;
+ALTER TABLE "StationMetrics" ADD COLUMN "yoyChangePct" REAL;
+ALTER TABLE "StationMetrics" ADD COLUMN "recoveryRatio" REAL;
+ALTER TABLE "StationMetrics" ADD COLUMN "recoveryBaselineYear" INTEGER;
@@ -0,0 +1,22 @@
+-- AlterTable
+ALTER TABLE "RidershipDaily" ADD COLUMN "dayType" TEXT;
+
+-- CreateTable
+CREATE TABLE "StationDayTypeMetrics" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "stationId" TEXT NOT NULL,
+    "dayType" TEXT NOT NULL,
+    "rolling30dAvg" REAL NOT NULL,
+    "rolling90dAvg" REAL NOT NULL,
+    "days90d" INTEGER NOT NULL,
+    "scheduledTrips" REAL,
+    "ridersPerTrain" REAL,
+    "ghostScore" INTEGER NOT NULL,
+    "modelVersion" TEXT,
+    "windowEnd" TEXT NOT NULL,
+    "lastUpdated" DATETIME NOT NULL,
+    CONSTRAINT "StationDayTypeMetrics_stationId_fkey" FOREIGN KEY ("stationId") REFERENCES "Station" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "StationDayTypeMetrics_stationId_dayType_key" ON "StationDayTypeMetrics"("stationId", "dayType");
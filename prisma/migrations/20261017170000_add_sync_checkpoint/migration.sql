@@ -0,0 +1,14 @@
+-- CreateTable
+CREATE TABLE "SyncCheckpoint" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "cityId" TEXT NOT NULL,
+    "dataset" TEXT NOT NULL,
+    "since" TEXT NOT NULL,
+    "rowOffset" INTEGER NOT NULL,
+    "lastDate" TEXT,
+    "updatedAt" DATETIME NOT NULL,
+    CONSTRAINT "SyncCheckpoint_cityId_fkey" FOREIGN KEY ("cityId") REFERENCES "City" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "SyncCheckpoint_cityId_dataset_key" ON "SyncCheckpoint"("cityId", "dataset");
@@ -0,0 +1,16 @@
+-- AlterTable
+ALTER TABLE "SyncCheckpoint" ADD COLUMN "updatedSince" TEXT;
+ALTER TABLE "SyncCheckpoint" ADD COLUMN "maxUpdatedAt" TEXT;
+
+-- CreateTable
+CREATE TABLE "SyncWatermark" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "cityId" TEXT NOT NULL,
+    "dataset" TEXT NOT NULL,
+    "updatedAt" TEXT NOT NULL,
+    "syncedAt" DATETIME NOT NULL,
+    CONSTRAINT "SyncWatermark_cityId_fkey" FOREIGN KEY ("cityId") REFERENCES "City" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "SyncWatermark_cityId_dataset_key" ON "SyncWatermark"("cityId", "dataset");
@@ -0,0 +1,3 @@
+-- AlterTable
+ALTER TABLE "Station" ADD COLUMN "active" BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE "Station" ADD COLUMN "deactivatedAt" DATETIME;
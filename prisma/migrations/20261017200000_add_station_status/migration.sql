@@ -0,0 +1,14 @@
+-- CreateTable
+CREATE TABLE "StationStatus" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "stationId" TEXT NOT NULL,
+    "status" TEXT NOT NULL,
+    "effectiveFrom" TEXT NOT NULL,
+    "effectiveTo" TEXT,
+    "note" TEXT,
+    "createdAt" DATETIME NOT NULL,
+    CONSTRAINT "StationStatus_stationId_fkey" FOREIGN KEY ("stationId") REFERENCES "Station" ("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "StationStatus_stationId_effectiveFrom_key" ON "StationStatus"("stationId", "effectiveFrom");
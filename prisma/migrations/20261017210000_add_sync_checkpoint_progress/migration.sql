@@ -0,0 +1,2 @@
+-- AlterTable
+ALTER TABLE "SyncCheckpoint" ADD COLUMN "progress" TEXT;
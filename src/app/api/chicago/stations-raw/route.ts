@@ -56,13 +56,15 @@ export async function GET(request: NextRequest) {
         m.lastDayEntries,
         m.serviceDateMax,
         CASE
+          -- Closed stations are not scored, but have data; show them as closed
+          WHEN m.dataStatus = 'closed' THEN 'closed'
           WHEN m.serviceDateMax IS NULL THEN 'missing'
           WHEN m.rolling30dAvg = 0 THEN 'zero'
           ELSE 'available'
         END as dataStatus
       FROM Station s
       LEFT JOIN StationMetrics m ON s.id = m.stationId
-      WHERE s.cityId = ?
+      WHERE s.cityId = ? AND s.active = 1
       ORDER BY ${orderClause}
     `, [chicagoCity.id]);
 
@@ -71,7 +73,7 @@ export async function GET(request: NextRequest) {
       SELECT MAX(serviceDateMax) as maxDate
       FROM StationMetrics m
       JOIN Station s ON m.stationId = s.id
-      WHERE s.cityId = ?
+      WHERE s.cityId = ? AND s.active = 1
     `, [chicagoCity.id]);
 
     await db.close();
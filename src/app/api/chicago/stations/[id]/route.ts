@@ -15,7 +15,8 @@ export async function GET(
       where: { id: stationId },
       include: {
         metrics: true,
-        city: true
+        city: true,
+        scoreExplanation: true
       }
     });
 
@@ -73,6 +74,26 @@ export async function GET(
 
     const percentile = Math.round((stationsWithLowerRidership / totalStations) * 100);
 
+    // Per-factor breakdown written by the ETL compute step
+    const scoreExplanation = station.scoreExplanation;
+    const breakdown = scoreExplanation
+      ? {
+          modelVersion: scoreExplanation.modelVersion,
+          rank: scoreExplanation.rank,
+          peerGroupSize: scoreExplanation.peerGroupSize,
+          windowStart: scoreExplanation.window30Start,
+          windowEnd: scoreExplanation.windowEnd,
+          computedAt: scoreExplanation.computedAt.toISOString(),
+          factors: (() => {
+            try {
+              return JSON.parse(scoreExplanation.factors || '[]');
+            } catch {
+              return [];
+            }
+          })()
+        }
+      : null;
+
     // Format response
     const response = {
       station: {
@@ -101,7 +122,8 @@ export async function GET(
         systemAverage: Math.round(systemAverage),
         explanation: station.metrics?.rolling30dAvg
           ? `This station has ${Math.round(((systemAverage - station.metrics.rolling30dAvg) / systemAverage) * 100)}% less ridership than the system average`
-          : "No ridership data available"
+          : "No ridership data available",
+        breakdown
       }
     };
 
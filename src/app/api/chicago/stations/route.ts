@@ -37,7 +37,8 @@ export async function GET(request: NextRequest) {
     // Get stations separately
     const allStations = await prisma.station.findMany({
       where: {
-        cityId: chicagoCity.id
+        cityId: chicagoCity.id,
+        active: true
       }
     });
 
@@ -1,11 +1,11 @@
 import { cn } from "@/lib/utils";
-import { getGhostScoreColor } from "@/lib/utils";
+import { closedStationColor, getGhostScoreColor } from "@/lib/utils";
 
 interface GhostScoreBadgeProps {
   score: number;
   size?: "sm" | "md" | "lg";
   showLabel?: boolean;
-  dataStatus?: 'available' | 'missing' | 'zero';
+  dataStatus?: 'available' | 'missing' | 'zero' | 'closed';
   className?: string;
 }
 
@@ -38,6 +38,23 @@ export default function GhostScoreBadge({
     );
   }
 
+  // Closed stations aren't scored
+  if (dataStatus === 'closed') {
+    return (
+      <div className={cn("flex flex-col items-center", className)}>
+        <div
+          className={cn("transition-all", sizeClasses[size])}
+          style={{ color: closedStationColor }}
+        >
+          —
+        </div>
+        {showLabel && (
+          <div className="text-ui-xs mt-1" style={{ color: closedStationColor }}>closed</div>
+        )}
+      </div>
+    );
+  }
+
   const color = getGhostScoreColor(score);
 
   return (
@@ -2,13 +2,13 @@
 
 import { useSpring, animated } from "@react-spring/web";
 import { springConfigs } from "@/lib/motion/tokens";
-import { cn, getGhostScoreColor } from "@/lib/utils";
+import { cn, closedStationColor, getGhostScoreColor } from "@/lib/utils";
 import { Ghost } from "lucide-react";
 
 interface GhostScoreGaugeProps {
   score: number;
   label?: string;
-  dataStatus?: "available" | "missing" | "zero";
+  dataStatus?: "available" | "missing" | "zero" | "closed";
   className?: string;
 }
 
@@ -18,12 +18,16 @@ export default function GhostScoreGauge({
   dataStatus = "available",
   className,
 }: GhostScoreGaugeProps) {
-  const color = dataStatus === "missing" ? "#9CA3AF" : getGhostScoreColor(score);
+  const unscored = dataStatus === "missing" || dataStatus === "closed";
+  const color =
+    dataStatus === "missing" ? "#9CA3AF" :
+    dataStatus === "closed" ? closedStationColor :
+    getGhostScoreColor(score);
 
   // Animated count-up with react-spring
   const { number } = useSpring({
     from: { number: 0 },
-    to: { number: dataStatus === "missing" ? 0 : score },
+    to: { number: unscored ? 0 : score },
     delay: 300,
     config: springConfigs.countUp,
   });
@@ -31,7 +35,7 @@ export default function GhostScoreGauge({
   // Animated gauge fill
   const { progress } = useSpring({
     from: { progress: 0 },
-    to: { progress: dataStatus === "missing" ? 0 : score / 100 },
+    to: { progress: unscored ? 0 : score / 100 },
     delay: 200,
     config: { tension: 280, friction: 60 },
   });
@@ -114,10 +118,10 @@ export default function GhostScoreGauge({
 
         {/* Center content */}
         <div className="absolute inset-0 flex flex-col items-center justify-center">
-          {dataStatus === "missing" ? (
+          {unscored ? (
             <>
-              <span className="text-3xl font-display font-bold text-gray-400">—</span>
-              <Ghost className="w-4 h-4 text-gray-400 mt-1" />
+              <span className="text-3xl font-display font-bold" style={{ color }}>—</span>
+              <Ghost className="w-4 h-4 mt-1" style={{ color }} />
             </>
           ) : (
             <>
@@ -154,11 +158,11 @@ export default function GhostScoreGauge({
 
       {/* Label */}
       <span className="stat-label-text mt-4">
-        {dataStatus === "missing" ? "No Data" : label}
+        {dataStatus === "missing" ? "No Data" : dataStatus === "closed" ? "Closed" : label}
       </span>
 
       {/* Pulsing ring for high ghost scores */}
-      {score > 80 && dataStatus !== "missing" && (
+      {score > 80 && !unscored && (
         <div className="absolute inset-0 flex items-center justify-center pointer-events-none">
           <div
             className="w-36 h-36 rounded-full ghost-score-high"
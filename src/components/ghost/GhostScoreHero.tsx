@@ -2,12 +2,12 @@
 
 import { useSpring, animated } from "@react-spring/web";
 import { springConfigs } from "@/lib/motion/tokens";
-import { cn, getGhostScoreColor } from "@/lib/utils";
+import { cn, closedStationColor, getGhostScoreColor } from "@/lib/utils";
 
 interface GhostScoreHeroProps {
   score: number;
   label?: string;
-  dataStatus?: "available" | "missing" | "zero";
+  dataStatus?: "available" | "missing" | "zero" | "closed";
   className?: string;
 }
 
@@ -34,13 +34,16 @@ export default function GhostScoreHero({
   dataStatus = "available",
   className,
 }: GhostScoreHeroProps) {
+  const unscored = dataStatus === "missing" || dataStatus === "closed";
   const color =
-    dataStatus === "missing" ? "#9CA3AF" : getGhostScoreColor(score);
+    dataStatus === "missing" ? "#9CA3AF" :
+    dataStatus === "closed" ? closedStationColor :
+    getGhostScoreColor(score);
 
   // Animated count-up with react-spring
   const { number } = useSpring({
     from: { number: 0 },
-    to: { number: dataStatus === "missing" ? 0 : score },
+    to: { number: unscored ? 0 : score },
     delay: 300,
     config: springConfigs.countUp,
   });
@@ -69,7 +72,7 @@ export default function GhostScoreHero({
 
       {/* Score */}
       <div className="relative z-10 flex flex-col items-center">
-        {dataStatus === "missing" ? (
+        {unscored ? (
           <span
             className="ghost-score-text"
             style={{
@@ -96,7 +99,7 @@ export default function GhostScoreHero({
         )}
 
         <span className="stat-label-text mt-2">
-          {dataStatus === "missing" ? "No Data" : label}
+          {dataStatus === "missing" ? "No Data" : dataStatus === "closed" ? "Closed" : label}
         </span>
       </div>
 
@@ -30,7 +30,7 @@ interface Station {
   ghostScore: number;
   rolling30dAvg: number;
   lastDayEntries: number;
-  dataStatus?: 'available' | 'missing' | 'zero';
+  dataStatus?: 'available' | 'missing' | 'zero' | 'closed';
 }
 
 interface MapContainerProps {
@@ -404,6 +404,7 @@ export default function MapContainer({ searchQuery = "" }: MapContainerProps) {
                   "circle-color": [
                     "case",
                     ["==", ["get", "dataStatus"], "missing"], "rgba(156, 163, 175, 0.15)",
+                    ["==", ["get", "dataStatus"], "closed"], "rgba(71, 85, 105, 0.15)",
                     [
                       "interpolate",
                       ["linear"],
@@ -439,6 +440,7 @@ export default function MapContainer({ searchQuery = "" }: MapContainerProps) {
                   "circle-color": [
                     "case",
                     ["==", ["get", "dataStatus"], "missing"], "#9CA3AF",
+                    ["==", ["get", "dataStatus"], "closed"], "#475569",
                     [
                       "interpolate",
                       ["linear"],
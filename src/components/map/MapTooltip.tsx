@@ -10,7 +10,7 @@ interface Station {
   lines: string[];
   ghostScore: number;
   rolling30dAvg: number;
-  dataStatus?: 'available' | 'missing' | 'zero';
+  dataStatus?: 'available' | 'missing' | 'zero' | 'closed';
 }
 
 interface MapTooltipProps {
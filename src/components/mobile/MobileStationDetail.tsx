@@ -19,7 +19,7 @@ interface Station {
   ghostScore: number;
   rolling30dAvg: number;
   lastDayEntries: number;
-  dataStatus?: 'available' | 'missing' | 'zero';
+  dataStatus?: 'available' | 'missing' | 'zero' | 'closed';
 }
 
 interface MobileStationDetailProps {
@@ -241,6 +241,8 @@ export default function MobileStationDetail({
               <p className="text-sm text-gray-600 leading-relaxed">
                 {station.dataStatus === 'missing' ? (
                   "No recent ridership data available for this station."
+                ) : station.dataStatus === 'closed' ? (
+                  "This station is closed, so it has no ghost score."
                 ) : station.ghostScore > 70 ? (
                   "This station qualifies as a ghost stop with extremely low ridership compared to the CTA system average."
                 ) : (
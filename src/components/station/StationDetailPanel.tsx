@@ -20,7 +20,28 @@ interface Station {
   ghostScore: number;
   rolling30dAvg: number;
   lastDayEntries: number;
-  dataStatus?: "available" | "missing" | "zero";
+  dataStatus?: "available" | "missing" | "zero" | "closed";
+}
+
+interface ScoreFactor {
+  input: string;
+  normalization: string;
+  available: boolean;
+  raw: number;
+  normalized: number;
+  weight: number;
+  contribution: number;
+  peerCount: number;
+}
+
+interface ScoreBreakdown {
+  modelVersion: string;
+  rank: number;
+  peerGroupSize: number;
+  windowStart: string;
+  windowEnd: string;
+  computedAt: string;
+  factors: ScoreFactor[];
 }
 
 interface StationDetail {
@@ -31,9 +52,20 @@ interface StationDetail {
     percentile: number;
     systemAverage: number;
     explanation: string;
+    breakdown?: ScoreBreakdown | null;
   };
 }
 
+// Labels for the ETL's scoring model inputs
+const FACTOR_LABELS: Record<string, string> = {
+  rolling30dAvg: "30-day ridership",
+  rolling90dAvg: "90-day ridership",
+  trend: "Recent trend",
+  weekdayWeekendRatio: "Weekday vs weekend",
+  ridersPerTrain: "Riders per train",
+  yoyChange: "Year over year",
+};
+
 interface StationDetailPanelProps {
   station: Station;
   onClose?: () => void;
@@ -181,6 +213,37 @@ export default function StationDetailPanel({
                     stations
                   </span>
                 </div>
+                {detail.metrics.breakdown &&
+                  detail.metrics.breakdown.factors.length > 0 && (
+                    <div className="mt-4 pt-4 border-t border-white/10">
+                      <div className="text-ui-xs text-text-tertiary mb-2">
+                        Rank {detail.metrics.breakdown.rank} of{" "}
+                        {detail.metrics.breakdown.peerGroupSize} ·{" "}
+                        {detail.metrics.breakdown.windowStart} to{" "}
+                        {detail.metrics.breakdown.windowEnd}
+                      </div>
+                      <ul className="space-y-1">
+                        {detail.metrics.breakdown.factors
+                          .filter((f) => f.available)
+                          .map((f) => (
+                            <li
+                              key={f.input}
+                              className="flex items-center justify-between text-ui-xs"
+                            >
+                              <span className="text-text-secondary">
+                                {FACTOR_LABELS[f.input] ?? f.input}
+                              </span>
+                              <span className="stat-value-text">
+                                +{f.contribution.toFixed(1)} pts
+                              </span>
+                            </li>
+                          ))}
+                      </ul>
+                      <div className="text-ui-xs text-text-tertiary mt-2">
+                        Model {detail.metrics.breakdown.modelVersion}
+                      </div>
+                    </div>
+                  )}
               </div>
             </motion.div>
 
@@ -22,7 +22,7 @@ interface Station {
   ghostScore: number;
   rolling30dAvg: number;
   lastDayEntries: number;
-  dataStatus?: "available" | "missing" | "zero";
+  dataStatus?: "available" | "missing" | "zero" | "closed";
 }
 
 interface StationListProps {
@@ -4,7 +4,7 @@ import { Users, TrendingUp, TrendingDown, Minus } from "lucide-react";
 import { useMemo } from "react";
 import CTALineBadge from "./CTALineBadge";
 import GhostScoreBadge from "@/components/ghost/GhostScoreBadge";
-import { cn } from "@/lib/utils";
+import { cn, closedStationColor } from "@/lib/utils";
 import { normalizeStationLines } from "@/lib/cta/normalizeStationLines";
 import { CTA_LINE_COLORS } from "@/lib/cta/explodeAndStitchSegments";
 
@@ -14,7 +14,7 @@ interface StationRowProps {
   lines: string[];
   ghostScore: number;
   dailyAverage: number;
-  dataStatus?: 'available' | 'missing' | 'zero';
+  dataStatus?: 'available' | 'missing' | 'zero' | 'closed';
   selected?: boolean;
   onClick?: () => void;
   className?: string;
@@ -36,6 +36,9 @@ export default function StationRow({
     return normalizeStationLines({ name, lines });
   }, [name, lines]);
 
+  // Closed stations aren't scored; show a full ring in their own color instead
+  const closed = dataStatus === 'closed';
+
   // Get primary line color for rank badge
   const primaryLineColor = normalizedLines[0] ? CTA_LINE_COLORS[normalizedLines[0]] : '#6B7280';
 
@@ -121,9 +124,11 @@ export default function StationRow({
                 fill="none"
                 pathLength="100"
                 strokeDasharray="100"
-                strokeDashoffset={100 - ghostScore}
+                strokeDashoffset={closed ? 0 : 100 - ghostScore}
+                style={closed ? { color: closedStationColor } : undefined}
                 className={cn(
                   "transition-all duration-500",
+                  closed ? "" :
                   ghostScore > 80 ? "text-red-500" :
                   ghostScore > 60 ? "text-orange-500" :
                   ghostScore > 40 ? "text-amber-500" :
@@ -131,11 +136,14 @@ export default function StationRow({
                 )}
               />
             </svg>
-            <span className="font-display font-bold text-lg">
-              {Math.round(ghostScore)}
+            <span
+              className="font-display font-bold text-lg"
+              style={closed ? { color: closedStationColor } : undefined}
+            >
+              {closed ? '—' : Math.round(ghostScore)}
             </span>
             <span className="text-[9px] font-medium text-text-tertiary uppercase tracking-wider">
-              Ghost
+              {closed ? 'Closed' : 'Ghost'}
             </span>
           </div>
         </div>
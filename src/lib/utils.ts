@@ -24,6 +24,9 @@ export function getGhostScoreColor(score: number): string {
   return "#22C55E" // green-500
 }
 
+// Color for stations that are closed and therefore have no ghost score
+export const closedStationColor = "#475569" // slate-600
+
 // CTA line color mapping
 export const ctaLineColors = {
   "Red": "#C60C30",